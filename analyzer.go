@@ -2,7 +2,6 @@ package cortex
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,6 +10,9 @@ import (
 const (
 	analyzersURL    = APIRoute + "/analyzer"
 	analyzersByType = analyzersURL + "/type/"
+
+	// cancelJobTimeout limits cancelling of an orphaned job
+	cancelJobTimeout = 10 * time.Second
 )
 
 // Analyzer defines a specific Cortex Analyzer
@@ -43,12 +45,19 @@ type AnalyzerService interface {
 	Run(context.Context, string, Observable, time.Duration) (*Report, error)
 	StartJob(context.Context, string, Observable) (*Job, *http.Response, error)
 	NewMultiRun(context.Context, time.Duration) *MultiRun
+	RunBatch(context.Context, []Observable, *BatchOpts) ([]*BatchResult, error)
 	DataTypes(context.Context) ([]string, error)
+	Definitions(context.Context) ([]AnalyzerDefinition, *http.Response, error)
+	Enable(context.Context, string, *Analyzer) (*Analyzer, *http.Response, error)
+	Update(context.Context, string, *AnalyzerUpdate) (*Analyzer, *http.Response, error)
+	Disable(context.Context, string) (*http.Response, error)
 }
 
 // AnalyzerServiceOp handles analyzer methods from Cortex API
 type AnalyzerServiceOp struct {
-	client *Client
+	client  *Client
+	limiter *rateLimiter
+	cache   *reportCache
 }
 
 // Get a specified Cortex analyzer by its name
@@ -69,13 +78,18 @@ func (a *AnalyzerServiceOp) Get(ctx context.Context, id string) (*Analyzer, *htt
 		return nil, resp, fmt.Errorf("no analyzer found with name %s", id)
 	}
 
-	req, err := a.client.NewRequest("GET", fmt.Sprintf(analyzersURL+"/%s", alid), nil)
+	return a.getByID(ctx, alid)
+}
+
+// getByID retrieves an analyzer by its Cortex ID
+func (a *AnalyzerServiceOp) getByID(ctx context.Context, id string) (*Analyzer, *http.Response, error) {
+	req, err := a.client.NewRequest("GET", fmt.Sprintf(analyzersURL+"/%s", id), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var an Analyzer
-	resp, err = a.client.Do(ctx, req, &an)
+	resp, err := a.client.Do(ctx, req, &an)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -85,33 +99,7 @@ func (a *AnalyzerServiceOp) Get(ctx context.Context, id string) (*Analyzer, *htt
 
 // List all Cortex analyzers with pagination
 func (a *AnalyzerServiceOp) List(ctx context.Context) ([]Analyzer, *http.Response, error) {
-	var analyzers []Analyzer
-
-	start := 0
-
-	for {
-		pagedURL := fmt.Sprintf("%s?range=%d-%d", analyzersURL, start, a.client.PageSize)
-		req, err := a.client.NewRequest("GET", pagedURL, nil)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		var paginatedAns []Analyzer
-		resp, err := a.client.Do(ctx, req, &paginatedAns)
-		if err != nil {
-			return nil, resp, err
-		}
-
-		if len(paginatedAns) < a.client.PageSize {
-			analyzers = append(analyzers, paginatedAns...)
-			break
-		}
-
-		analyzers = append(analyzers, paginatedAns...)
-		start = start + a.client.PageSize
-	}
-
-	return analyzers, nil, nil
+	return listPages[Analyzer](ctx, a.client, analyzersURL)
 }
 
 // ListByType lists Cortex analyzers by datatype
@@ -143,23 +131,36 @@ func (a *AnalyzerServiceOp) Run(ctx context.Context, anid string, o Observable,
 
 // run is a more lighter version that uses Cortex Analyzer ID directly
 func (a *AnalyzerServiceOp) run(ctx context.Context, id string, o Observable, d time.Duration) (*Report, error) {
+	if t, ok := o.(*Task); ok && a.cache != nil {
+		return a.cache.do(ctx, a, id, t, func(ctx context.Context) (*Report, error) {
+			return a.runJob(ctx, id, o, d)
+		})
+	}
+
+	return a.runJob(ctx, id, o, d)
+}
+
+// runJob starts a new job and waits for its report
+func (a *AnalyzerServiceOp) runJob(ctx context.Context, id string, o Observable, d time.Duration) (*Report, error) {
 	j, _, err := a.StartJob(ctx, id, o)
 	if err != nil {
 		return nil, err
 	}
 
 	jso := &JobServiceOp{a.client}
-	report, resp, err := jso.WaitReport(ctx, j.ID, d)
+	report, _, err := jso.WaitReport(ctx, j.ID, d)
 	if err != nil {
-		if resp != nil && resp.StatusCode == 500 {
-			return nil, fmt.Errorf("job passed maximum execution time %s", d.String())
+		if ctx.Err() != nil {
+			// the caller has gone, so don't leave the job running
+			actx, cancel := context.WithTimeout(context.Background(), cancelJobTimeout)
+			defer cancel()
+			if _, cerr := jso.Cancel(actx, j.ID, false); cerr != nil {
+				return nil, fmt.Errorf("%w, job %s is left running: %v", err, j.ID, cerr)
+			}
+
+			return nil, err
 		}
-
-		if resp != nil && resp.StatusCode == 429 {
-			return nil, errors.New("rate limit exceeded")
-		}
-
-		return nil, err
+		return nil, waitReportError(err, d)
 	}
 
 	return report, err
@@ -170,6 +171,12 @@ func (a *AnalyzerServiceOp) StartJob(ctx context.Context, anid string, o Observa
 	var req *http.Request
 	var err error
 
+	if a.limiter != nil {
+		if err := a.limiter.wait(ctx, a, anid); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	switch o.Type() {
 	case "file":
 		obsData := o.(*FileTask)
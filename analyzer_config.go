@@ -0,0 +1,141 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	analyzerConfigsURL = APIRoute + "/analyzerconfig"
+
+	// GlobalConfigName is the name of the base configuration shared by all
+	// analyzers
+	GlobalConfigName = "global"
+)
+
+// AnalyzerConfig represents a base configuration shared by analyzers with
+// the same BaseConfig value, e.g. "global" or "VirusTotal"
+type AnalyzerConfig struct {
+	Name               string                 `json:"name"`
+	ConfigurationItems []ConfigurationItem    `json:"configurationItems"`
+	Config             map[string]interface{} `json:"config"`
+}
+
+// CommonConfig represents well-known settings that could be set in any base
+// configuration. Nil fields are not changed on update.
+type CommonConfig struct {
+	ProxyHTTP            *string `json:"proxy_http,omitempty"`
+	ProxyHTTPS           *string `json:"proxy_https,omitempty"`
+	CheckTLP             *bool   `json:"check_tlp,omitempty"`
+	MaxTLP               *TLP    `json:"max_tlp,omitempty"`
+	CheckPAP             *bool   `json:"check_pap,omitempty"`
+	MaxPAP               *PAP    `json:"max_pap,omitempty"`
+	AutoExtractArtifacts *bool   `json:"auto_extract_artifacts,omitempty"`
+	JobCache             *int    `json:"jobCache,omitempty"`   // in minutes
+	JobTimeout           *int    `json:"jobTimeout,omitempty"` // in minutes
+}
+
+// Common decodes well-known settings from the configuration
+func (a *AnalyzerConfig) Common() (*CommonConfig, error) {
+	b, err := json.Marshal(a.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var c CommonConfig
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// AnalyzerConfigService is an interface for managing analyzers base
+// configurations
+type AnalyzerConfigService interface {
+	List(context.Context) ([]AnalyzerConfig, *http.Response, error)
+	Get(context.Context, string) (*AnalyzerConfig, *http.Response, error)
+	Update(context.Context, string, interface{}) (*AnalyzerConfig, *http.Response, error)
+}
+
+// AnalyzerConfigServiceOp handles analyzer base configuration methods from
+// Cortex API
+type AnalyzerConfigServiceOp struct {
+	client *Client
+}
+
+// List all base configurations of the organization
+func (a *AnalyzerConfigServiceOp) List(ctx context.Context) ([]AnalyzerConfig, *http.Response, error) {
+	req, err := a.client.NewRequest("GET", analyzerConfigsURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cfgs []AnalyzerConfig
+	resp, err := a.client.Do(ctx, req, &cfgs)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cfgs, resp, nil
+}
+
+// Get a base configuration by its name
+func (a *AnalyzerConfigServiceOp) Get(ctx context.Context, name string) (*AnalyzerConfig, *http.Response, error) {
+	req, err := a.client.NewRequest("GET", fmt.Sprintf(analyzerConfigsURL+"/%s", name), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c AnalyzerConfig
+	resp, err := a.client.Do(ctx, req, &c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &c, resp, nil
+}
+
+// Update a base configuration by its name. The config is either
+// a map[string]interface{} or a struct like *CommonConfig, which is
+// marshalled to JSON. Cortex replaces the whole configuration, so its
+// settings are merged into the stored ones, which are kept unless set.
+func (a *AnalyzerConfigServiceOp) Update(ctx context.Context, name string, config interface{}) (*AnalyzerConfig, *http.Response, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	var changed map[string]interface{}
+	if err := json.Unmarshal(b, &changed); err != nil {
+		return nil, nil, fmt.Errorf("config is not a JSON object: %w", err)
+	}
+
+	stored, resp, err := a.Get(ctx, name)
+	if err != nil {
+		return nil, resp, err
+	}
+	merged := make(map[string]interface{}, len(stored.Config)+len(changed))
+	for k, v := range stored.Config {
+		merged[k] = v
+	}
+	for k, v := range changed {
+		merged[k] = v
+	}
+
+	req, err := a.client.NewRequest("PATCH", fmt.Sprintf(analyzerConfigsURL+"/%s", name), map[string]interface{}{
+		"config": merged,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c AnalyzerConfig
+	resp, err = a.client.Do(ctx, req, &c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &c, resp, nil
+}
@@ -0,0 +1,95 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateAnalyzerConfig(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var patched bool
+	mux.HandleFunc("/"+analyzerConfigsURL+"/"+GlobalConfigName, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write(storedConfigJSON)
+			return
+		case "PATCH":
+			patched = true
+		default:
+			t.Errorf("method = %s, want GET or PATCH", r.Method)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		// the stored settings which are not updated are kept
+		want := map[string]interface{}{
+			"config": map[string]interface{}{
+				"proxy_http":             "http://proxy:3128",
+				"check_tlp":              true,
+				"max_tlp":                float64(2),
+				"jobCache":               float64(10),
+				"auto_extract_artifacts": true,
+			},
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("update body = %+v, want %+v", body, want)
+		}
+
+		w.Write(globalConfigJSON)
+	})
+
+	proxy := "http://proxy:3128"
+	check := true
+	got, _, err := client.AnalyzerConfigs.Update(context.Background(), GlobalConfigName, &CommonConfig{
+		ProxyHTTP: &proxy,
+		CheckTLP:  &check,
+		MaxTLP:    &TLPAmber,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzerConfigs.Update returned error: %v", err)
+	}
+
+	if !patched {
+		t.Error("the configuration is not updated")
+	}
+
+	common, err := got.Common()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *common.ProxyHTTP != proxy || !*common.CheckTLP || *common.MaxTLP != TLPAmber || *common.JobCache != 10 {
+		t.Errorf("AnalyzerConfig.Common = %+v, want values from %s", common, globalConfigJSON)
+	}
+}
+
+var globalConfigJSON = []byte(`
+{
+  "name": "global",
+  "configurationItems": [
+    {"name": "proxy_http", "description": "Url of the proxy", "type": "string", "multi": false, "required": false}
+  ],
+  "config": {
+    "proxy_http": "http://proxy:3128",
+    "check_tlp": true,
+    "max_tlp": 2,
+    "jobCache": 10
+  }
+}`)
+
+var storedConfigJSON = []byte(`
+{
+  "name": "global",
+  "config": {
+    "proxy_http": "http://old-proxy:3128",
+    "check_tlp": false,
+    "jobCache": 10,
+    "auto_extract_artifacts": true
+  }
+}`)
@@ -0,0 +1,185 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	analyzerDefinitionsURL  = APIRoute + "/analyzerdefinition"
+	organizationAnalyzerURL = organizationsURL + "/analyzer"
+)
+
+// AnalyzerDefinition describes an analyzer available for Cortex, that could
+// be enabled for an organization
+type AnalyzerDefinition struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name"`
+	Version            string              `json:"version"`
+	Description        string              `json:"description"`
+	Author             string              `json:"author"`
+	URL                string              `json:"url"`
+	License            string              `json:"license"`
+	BaseConfig         string              `json:"baseConfig"`
+	DataTypeList       []string            `json:"dataTypeList"`
+	ConfigurationItems []ConfigurationItem `json:"configurationItems"`
+	Command            string              `json:"command,omitempty"`
+	DockerImage        string              `json:"dockerImage,omitempty"`
+}
+
+// ConfigurationItem describes a configuration parameter of an analyzer
+type ConfigurationItem struct {
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	Type         string      `json:"type"`
+	Multi        bool        `json:"multi"`
+	Required     bool        `json:"required"`
+	DefaultValue interface{} `json:"defaultValue,omitempty"`
+}
+
+// AnalyzerUpdate holds fields of an enabled analyzer to change by Update,
+// empty and nil fields are left as they are
+type AnalyzerUpdate struct {
+	Name          string
+	Configuration map[string]interface{}
+	JobCache      interface{}
+
+	// Rate limits the analyzer to Rate jobs per RateUnit, a zero Rate
+	// removes the limit
+	Rate     *int
+	RateUnit string
+}
+
+// Definitions lists all analyzer definitions known by Cortex
+func (a *AnalyzerServiceOp) Definitions(ctx context.Context) ([]AnalyzerDefinition, *http.Response, error) {
+	if err := a.definitionsSupported(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := a.client.NewRequest("GET", analyzerDefinitionsURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var defs []AnalyzerDefinition
+	resp, err := a.client.Do(ctx, req, &defs)
+	if err != nil {
+		return nil, resp, unsupportedError(err)
+	}
+
+	return defs, resp, nil
+}
+
+// Enable the analyzer by its definition ID for the current user's
+// organization. Name, Configuration, Rate, RateUnit and JobCache of the
+// analyzer are used, Name defaults to the definition ID.
+func (a *AnalyzerServiceOp) Enable(ctx context.Context, defid string, an *Analyzer) (*Analyzer, *http.Response, error) {
+	if err := a.definitionsSupported(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	fields := analyzerFields(an)
+	if _, ok := fields["name"]; !ok {
+		fields["name"] = defid
+	}
+
+	req, err := a.client.NewRequest("POST", fmt.Sprintf(organizationAnalyzerURL+"/%s", defid), fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var enabled Analyzer
+	resp, err := a.client.Do(ctx, req, &enabled)
+	if err != nil {
+		return nil, resp, unsupportedError(err)
+	}
+
+	return &enabled, resp, nil
+}
+
+// Update the enabled analyzer's name, configuration, rate limit and job
+// cache by its ID
+func (a *AnalyzerServiceOp) Update(ctx context.Context, id string, u *AnalyzerUpdate) (*Analyzer, *http.Response, error) {
+	if err := a.definitionsSupported(ctx); err != nil {
+		return nil, nil, err
+	}
+	if u == nil {
+		u = &AnalyzerUpdate{}
+	}
+
+	fields := analyzerFields(&Analyzer{
+		Name:          u.Name,
+		Configuration: u.Configuration,
+		JobCache:      u.JobCache,
+	})
+	if u.Rate != nil {
+		if *u.Rate > 0 {
+			fields["rate"] = *u.Rate
+			fields["rateUnit"] = u.RateUnit
+		} else {
+			fields["rate"] = nil
+			fields["rateUnit"] = nil
+		}
+	}
+
+	req, err := a.client.NewRequest("PATCH", fmt.Sprintf(analyzersURL+"/%s", id), fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updated Analyzer
+	resp, err := a.client.Do(ctx, req, &updated)
+	if err != nil {
+		return nil, resp, unsupportedError(err)
+	}
+
+	return &updated, resp, nil
+}
+
+// Disable the analyzer for the organization by its ID
+func (a *AnalyzerServiceOp) Disable(ctx context.Context, id string) (*http.Response, error) {
+	if err := a.definitionsSupported(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := a.client.NewRequest("DELETE", fmt.Sprintf(analyzersURL+"/%s", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.Do(ctx, req, nil)
+	return resp, unsupportedError(err)
+}
+
+// definitionsSupported returns an error matching ErrUnsupported if the
+// server doesn't support managing analyzers by their definitions
+func (a *AnalyzerServiceOp) definitionsSupported(ctx context.Context) error {
+	return a.client.supports(ctx, "analyzer definitions", func(c *Capabilities) bool {
+		return c.AnalyzerDefinitions
+	})
+}
+
+// analyzerFields returns writable analyzer fields which are set
+func analyzerFields(an *Analyzer) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if an == nil {
+		return fields
+	}
+
+	if an.Name != "" {
+		fields["name"] = an.Name
+	}
+	if an.Configuration != nil {
+		fields["configuration"] = an.Configuration
+	}
+	if an.Rate > 0 {
+		fields["rate"] = an.Rate
+		fields["rateUnit"] = an.RateUnit
+	}
+	if an.JobCache != nil {
+		fields["jobCache"] = an.JobCache
+	}
+
+	return fields
+}
@@ -2,8 +2,13 @@ package cortex
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"os"
+	"regexp"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,8 +19,54 @@ type MultiRun struct {
 	ctx      context.Context
 	OnReport func(*Report)
 	OnError  func(error, Observable, *Analyzer)
+
+	// TempDir is a directory for spooling file observables that don't
+	// support random access, it defaults to os.TempDir
+	TempDir string
+
+	// Score computes a verdict of reports returned by Collect, it defaults
+	// to MaxLevel
+	Score func([]*Report) TaxonomyLevel
+
+	// MaxInFlight limits a number of analyzers running at once during a
+	// single Do call, zero means no limit
+	MaxInFlight int
+
+	// Include limits analyzers to the ones with these names, empty means
+	// all analyzers
+	Include []string
+
+	// IncludeRegexp limits analyzers to the ones with matching names
+	IncludeRegexp *regexp.Regexp
+
+	// Exclude skips analyzers with these names
+	Exclude []string
+
+	// ExcludeRegexp skips analyzers with matching names
+	ExcludeRegexp *regexp.Regexp
+
+	// SkipActiveOnPAPRed skips analyzers that actively probe the observable
+	// if its PAP is Red, since such actions are detectable by the target
+	SkipActiveOnPAPRed bool
+
+	// ActiveRegexp matches names of the analyzers skipped by
+	// SkipActiveOnPAPRed, it defaults to DefaultActiveRegexp
+	ActiveRegexp *regexp.Regexp
+
+	// Timeouts overrides Timeout for analyzers by name
+	Timeouts map[string]time.Duration
+
+	// onResult is called after each analyzer finishes, it's used by Collect
+	onResult func(*Analyzer, *Report, error, time.Duration)
+
+	// sem limits analyzers running at once, it's set by Do
+	sem chan struct{}
 }
 
+// DefaultActiveRegexp matches names of the analyzers that interact with
+// the target itself, e.g. scan, resolve or fetch it
+var DefaultActiveRegexp = regexp.MustCompile(`(?i)(nmap|scan|ping|traceroute|crawl|screenshot|fetch|probe)`)
+
 // NewMultiRun is a function that bootstraps MultiRun struct
 func (a *AnalyzerServiceOp) NewMultiRun(ctx context.Context, d time.Duration) *MultiRun {
 	return &MultiRun{
@@ -32,6 +83,19 @@ func (m *MultiRun) Do(o Observable) error {
 		return err
 	}
 
+	return m.do(o, ans)
+}
+
+// do analyzes an observable with the analyzers left after filtering
+func (m *MultiRun) do(o Observable, ans []Analyzer) error {
+	ans = m.filter(o, ans)
+
+	if m.MaxInFlight > 0 {
+		mc := *m
+		mc.sem = make(chan struct{}, m.MaxInFlight)
+		m = &mc
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(len(ans))
 	defer wg.Wait()
@@ -52,75 +116,276 @@ func (m *MultiRun) Do(o Observable) error {
 	return nil
 }
 
-// AnalyzeFile analyses a file observable by multiple analyzers
+// filter returns analyzers selected by the include and exclude options
+func (m *MultiRun) filter(o Observable, ans []Analyzer) []Analyzer {
+	active := m.ActiveRegexp
+	if active == nil {
+		active = DefaultActiveRegexp
+	}
+	skipActive := m.SkipActiveOnPAPRed && observablePAP(o) == PAPRed
+
+	var filtered []Analyzer
+	for _, an := range ans {
+		if len(m.Include) > 0 && !contains(m.Include, an.Name) {
+			continue
+		}
+		if m.IncludeRegexp != nil && !m.IncludeRegexp.MatchString(an.Name) {
+			continue
+		}
+		if contains(m.Exclude, an.Name) {
+			continue
+		}
+		if m.ExcludeRegexp != nil && m.ExcludeRegexp.MatchString(an.Name) {
+			continue
+		}
+		if skipActive && active.MatchString(an.Name) {
+			continue
+		}
+		filtered = append(filtered, an)
+	}
+
+	return filtered
+}
+
+// observablePAP returns PAP of the observable, Cortex uses Amber by default
+func observablePAP(o Observable) PAP {
+	var pap *PAP
+	switch o := o.(type) {
+	case *Task:
+		pap = o.PAP
+	case *FileTask:
+		pap = o.PAP
+	}
+	if pap == nil {
+		return PAPAmber
+	}
+
+	return *pap
+}
+
+func contains(ss []string, s string) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AnalyzeFile analyses a file observable by multiple analyzers. The file is
+// read once: it is used directly if its reader supports random access
+// (e.g. *os.File or *bytes.Reader), otherwise it is spooled to a temporary
+// file. Each analyzer uploads its own section reader independently, so
+// a stalled upload doesn't block the others. If the file can't be read, the
+// error is passed to OnError for every analyzer and returned.
 func (m *MultiRun) AnalyzeFile(wg *sync.WaitGroup, ft *FileTask, ans ...Analyzer) error {
-	var (
-		readPipes  []*io.PipeReader
-		writePipes []*io.PipeWriter
-	)
+	src, err := spoolFile(ft.Reader, m.TempDir)
+	if err != nil {
+		err = fmt.Errorf("can't read file %s: %w", ft.FileName, err)
+		for i := range ans {
+			m.handle(ft, &ans[i], nil, err, 0)
+			wg.Done()
+		}
+		return err
+	}
 
-	for i := range ans {
-		fr, fw := io.Pipe()
-		readPipes = append(readPipes, fr)
-		writePipes = append(writePipes, fw)
+	// the last finished analyzer releases the source
+	remaining := int32(len(ans))
+	release := func() {
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			src.close()
+		}
+	}
+	if len(ans) == 0 {
+		src.close()
+	}
 
+	for i := range ans {
 		o := &FileTask{
 			FileName:     ft.FileName,
-			Reader:       fr,
+			Reader:       io.NewSectionReader(src.r, src.off, src.size),
 			FileTaskMeta: ft.FileTaskMeta,
 		}
 
-		go func(an Analyzer) error {
+		go func(an Analyzer) {
 			defer wg.Done()
+			defer release()
+			m.runOne(o, &an)
+		}(ans[i])
+	}
 
-			report, err := m.aso.run(m.ctx, an.ID, o, m.Timeout)
-			if err != nil && m.OnError != nil {
-				m.OnError(err, o, &an)
-			}
-			if err == nil && report != nil && m.OnReport != nil {
-				m.OnReport(report)
+	return nil
+}
+
+// fileSource is a random access file content shared by analyzers
+type fileSource struct {
+	r     io.ReaderAt
+	off   int64
+	size  int64
+	close func()
+}
+
+// spoolFile returns r itself if it supports random access and its size is
+// known, otherwise it copies r to a temporary file in dir
+func spoolFile(r io.Reader, dir string) (*fileSource, error) {
+	if ra, ok := r.(io.ReaderAt); ok {
+		var off int64
+		if s, ok := r.(io.Seeker); ok {
+			cur, err := s.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
 			}
+			off = cur
+		}
 
-			return nil
-		}(ans[i])
+		if size, ok := readerSize(r); ok {
+			return &fileSource{r: ra, off: off, size: size, close: func() {}}, nil
+		}
 	}
 
-	wr := make([]io.Writer, len(writePipes))
-	for i := range writePipes {
-		wr[i] = writePipes[i]
+	f, err := os.CreateTemp(dir, "go-cortex-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
 	}
 
-	mw := io.MultiWriter(wr...)
-	go func() error {
-		if _, err := io.Copy(mw, ft.Reader); err != nil {
-			return err
-		}
-		for i := range writePipes {
-			writePipes[i].Close()
-		}
-		return nil
-	}()
+	size, err := io.Copy(f, r)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
 
-	return nil
+	return &fileSource{r: f, size: size, close: cleanup}, nil
 }
 
 // AnalyzeString analyses a basic string-alike observable by multiple analyzers
 func (m *MultiRun) AnalyzeString(wg *sync.WaitGroup, t *Task, ans ...Analyzer) error {
 	for i := range ans {
-		go func(an Analyzer) error {
+		go func(an Analyzer) {
 			defer wg.Done()
-
-			report, err := m.aso.run(m.ctx, an.ID, t, m.Timeout)
-			if err != nil && m.OnError != nil {
-				m.OnError(err, t, &an)
-			}
-			if err == nil && report != nil && m.OnReport != nil {
-				m.OnReport(report)
-			}
-
-			return nil
+			m.runOne(t, &an)
 		}(ans[i])
 	}
 
 	return nil
 }
+
+// runOne analyses the observable by the analyzer and calls handlers
+func (m *MultiRun) runOne(o Observable, an *Analyzer) {
+	if m.sem != nil {
+		select {
+		case m.sem <- struct{}{}:
+			defer func() { <-m.sem }()
+		case <-m.ctx.Done():
+			m.handle(o, an, nil, m.ctx.Err(), 0)
+			return
+		}
+	}
+
+	timeout := m.Timeout
+	if d, ok := m.Timeouts[an.Name]; ok {
+		timeout = d
+	}
+
+	start := time.Now()
+	report, err := m.aso.run(m.ctx, an.ID, o, timeout)
+	m.handle(o, an, report, err, time.Since(start))
+}
+
+// handle passes the analyzer's result to the handlers
+func (m *MultiRun) handle(o Observable, an *Analyzer, report *Report, err error, d time.Duration) {
+	if err != nil && m.OnError != nil {
+		m.OnError(err, o, an)
+	}
+	if err == nil && report != nil && m.OnReport != nil {
+		m.OnReport(report)
+	}
+	if m.onResult != nil {
+		m.onResult(an, report, err, d)
+	}
+}
+
+// MultiResult represents results of all analyzers collected by
+// MultiRun.Collect
+type MultiResult struct {
+	// Reports are sorted by analyzer name
+	Reports []*Report
+
+	// Errors maps analyzer name to its error
+	Errors map[string]error
+
+	// Durations maps analyzer name to its run duration
+	Durations map[string]time.Duration
+
+	// Verdict is computed by MultiRun.Score
+	Verdict TaxonomyLevel
+}
+
+// Collect analyzes an observable with all appropriate analyzers like Do and
+// returns all their results at once. OnReport and OnError are still called.
+func (m *MultiRun) Collect(o Observable) (*MultiResult, error) {
+	ans, _, err := m.aso.ListByType(m.ctx, o.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	return m.collect(o, ans)
+}
+
+// collect analyzes an observable with given analyzers and collects results
+func (m *MultiRun) collect(o Observable, ans []Analyzer) (*MultiResult, error) {
+	res := &MultiResult{
+		Errors:    make(map[string]error),
+		Durations: make(map[string]time.Duration),
+	}
+
+	var mu sync.Mutex
+	mc := *m
+	mc.onResult = func(an *Analyzer, r *Report, err error, d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		res.Durations[an.Name] = d
+		if err != nil {
+			res.Errors[an.Name] = err
+			return
+		}
+		if r != nil {
+			res.Reports = append(res.Reports, r)
+		}
+	}
+
+	if err := mc.do(o, ans); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(res.Reports, func(i, j int) bool {
+		return res.Reports[i].AnalyzerName < res.Reports[j].AnalyzerName
+	})
+
+	score := m.Score
+	if score == nil {
+		score = MaxLevel
+	}
+	res.Verdict = score(res.Reports)
+
+	return res, nil
+}
+
+// MaxLevel returns the most severe taxonomy level of all reports:
+// malicious > suspicious > info > safe. It returns an empty level if there
+// are no taxonomies.
+func MaxLevel(rs []*Report) TaxonomyLevel {
+	var max TaxonomyLevel
+	for _, r := range rs {
+		if l := r.MaxLevel(); max.Less(l) {
+			max = l
+		}
+	}
+
+	return max
+}
@@ -0,0 +1,251 @@
+package cortex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setupMulti registers two ip analyzers: MaxMind that succeeds and
+// VirusTotal that fails
+func setupMulti(mux *http.ServeMux) {
+	mux.HandleFunc("/"+analyzersByType+"ip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"maxmind","name":"MaxMind_GeoIP_3_0"},{"id":"vt","name":"VirusTotal_GetReport_3_0"}]`))
+	})
+	for _, id := range []string{"maxmind", "vt"} {
+		id := id
+		mux.HandleFunc("/"+analyzersURL+"/"+id+"/run", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id":"job-%s"}`, id)
+		})
+	}
+	mux.HandleFunc("/"+jobsURL+"/job-maxmind/waitreport", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "job-maxmind",
+			"analyzerName": "MaxMind_GeoIP_3_0",
+			"status": "Success",
+			"report": {
+				"success": true,
+				"summary": {"taxonomies": [
+					{"namespace": "MaxMind", "predicate": "Location", "value": "Russia", "level": "info"},
+					{"namespace": "MaxMind", "predicate": "Blacklist", "value": "hit", "level": "suspicious"}
+				]}
+			}
+		}`))
+	})
+	mux.HandleFunc("/"+jobsURL+"/job-vt/waitreport", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+}
+
+func TestMultiRunCollect(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+	setupMulti(mux)
+
+	var reported int
+	mul := client.Analyzers.NewMultiRun(context.Background(), time.Minute)
+	mul.OnReport = func(*Report) {
+		reported++
+	}
+
+	res, err := mul.Collect(&Task{Data: "8.8.8.8", DataType: "ip"})
+	if err != nil {
+		t.Fatalf("MultiRun.Collect returned error: %v", err)
+	}
+
+	if len(res.Reports) != 1 || res.Reports[0].AnalyzerName != "MaxMind_GeoIP_3_0" {
+		t.Errorf("MultiResult.Reports = %+v, want MaxMind report", res.Reports)
+	}
+	if err := res.Errors["VirusTotal_GetReport_3_0"]; !IsTimeout(err) {
+		t.Errorf("VirusTotal error = %v, want timeout", err)
+	}
+	if len(res.Durations) != 2 {
+		t.Errorf("MultiResult.Durations = %v, want 2 durations", res.Durations)
+	}
+	if res.Verdict != TxSuspicious {
+		t.Errorf("MultiResult.Verdict = %s, want %s", res.Verdict, TxSuspicious)
+	}
+	if reported != 1 {
+		t.Errorf("OnReport called %d times, want 1", reported)
+	}
+
+	mul.Score = func([]*Report) TaxonomyLevel {
+		return TxSafe
+	}
+	res, err = mul.Collect(&Task{Data: "8.8.8.8", DataType: "ip"})
+	if err != nil {
+		t.Fatalf("MultiRun.Collect returned error: %v", err)
+	}
+	if res.Verdict != TxSafe {
+		t.Errorf("MultiResult.Verdict = %s, want custom %s", res.Verdict, TxSafe)
+	}
+}
+
+func TestMultiRunFile(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	content := strings.Repeat("MZ", 1<<16)
+
+	mux.HandleFunc("/"+analyzersByType+"file", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"yara","name":"Yara_2_0"},{"id":"pe","name":"File_Info_7_0"}]`))
+	})
+	for _, id := range []string{"yara", "pe"} {
+		id := id
+		mux.HandleFunc("/"+analyzersURL+"/"+id+"/run", func(w http.ResponseWriter, r *http.Request) {
+			f, _, err := r.FormFile("attachment")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			b, _ := io.ReadAll(f)
+			if string(b) != content {
+				t.Errorf("%s got %d bytes, want %d", id, len(b), len(content))
+			}
+			fmt.Fprintf(w, `{"id":"job-%s"}`, id)
+		})
+		mux.HandleFunc("/"+jobsURL+"/job-"+id+"/waitreport", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id":"job-%s","report":{"success":true}}`, id)
+		})
+	}
+
+	var (
+		mu       sync.Mutex
+		reported int
+		failed   int
+	)
+	mul := client.Analyzers.NewMultiRun(context.Background(), time.Minute)
+	mul.OnReport = func(*Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported++
+	}
+	mul.OnError = func(error, Observable, *Analyzer) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed++
+	}
+
+	// a reader without random access is spooled to a temporary file
+	err := mul.Do(&FileTask{
+		FileTaskMeta: FileTaskMeta{DataType: "file"},
+		FileName:     "sample.exe",
+		Reader:       io.MultiReader(strings.NewReader(content)),
+	})
+	if err != nil || reported != 2 || failed != 0 {
+		t.Fatalf("MultiRun.Do returned %v with %d reports and %d errors", err, reported, failed)
+	}
+
+	reported = 0
+	errRead := errors.New("disk is on fire")
+	err = mul.Do(&FileTask{
+		FileTaskMeta: FileTaskMeta{DataType: "file"},
+		FileName:     "sample.exe",
+		Reader:       io.MultiReader(strings.NewReader(content), &errReader{errRead}),
+	})
+	if !errors.Is(err, errRead) {
+		t.Errorf("MultiRun.Do returned %v, want %v", err, errRead)
+	}
+	if reported != 0 || failed != 2 {
+		t.Errorf("got %d reports and %d errors, want 0 and 2", reported, failed)
+	}
+}
+
+func TestMultiRunFilter(t *testing.T) {
+	ans := []Analyzer{
+		{Name: "MaxMind_GeoIP_3_0"},
+		{Name: "Nmap_Scan_1_0"},
+		{Name: "VirusTotal_GetReport_3_0"},
+		{Name: "Shodan_Host_1_0"},
+	}
+	red := PAPRed
+
+	var tests = []struct {
+		mul  *MultiRun
+		o    Observable
+		want []string
+	}{
+		{&MultiRun{}, &Task{}, []string{"MaxMind_GeoIP_3_0", "Nmap_Scan_1_0", "VirusTotal_GetReport_3_0", "Shodan_Host_1_0"}},
+		{&MultiRun{Include: []string{"Shodan_Host_1_0"}}, &Task{}, []string{"Shodan_Host_1_0"}},
+		{&MultiRun{IncludeRegexp: regexp.MustCompile(`^V`)}, &Task{}, []string{"VirusTotal_GetReport_3_0"}},
+		{&MultiRun{Exclude: []string{"Shodan_Host_1_0"}, ExcludeRegexp: regexp.MustCompile(`^Max`)}, &Task{}, []string{"Nmap_Scan_1_0", "VirusTotal_GetReport_3_0"}},
+		{&MultiRun{SkipActiveOnPAPRed: true}, &Task{}, []string{"MaxMind_GeoIP_3_0", "Nmap_Scan_1_0", "VirusTotal_GetReport_3_0", "Shodan_Host_1_0"}},
+		{&MultiRun{SkipActiveOnPAPRed: true}, &Task{PAP: &red}, []string{"MaxMind_GeoIP_3_0", "VirusTotal_GetReport_3_0", "Shodan_Host_1_0"}},
+	}
+
+	for i, tt := range tests {
+		var got []string
+		for _, an := range tt.mul.filter(tt.o, ans) {
+			got = append(got, an.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: filter returned %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestMultiRunMaxInFlight(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var (
+		mu            sync.Mutex
+		running, peak int
+	)
+	mux.HandleFunc("/"+analyzersByType+"domain", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"a1","name":"A1"},{"id":"a2","name":"A2"},{"id":"a3","name":"A3"},{"id":"a4","name":"A4"}]`))
+	})
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		id := id
+		mux.HandleFunc("/"+analyzersURL+"/"+id+"/run", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id":"job-%s"}`, id)
+		})
+		mux.HandleFunc("/"+jobsURL+"/job-"+id+"/waitreport", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			want := "60.00seconds"
+			if id == "a1" {
+				want = "1.00seconds"
+			}
+			if got := r.URL.Query().Get("atMost"); got != want {
+				t.Errorf("%s waits for %s, want %s", id, got, want)
+			}
+			fmt.Fprintf(w, `{"id":"job-%s","report":{"success":true}}`, id)
+		})
+	}
+
+	mul := client.Analyzers.NewMultiRun(context.Background(), time.Minute)
+	mul.MaxInFlight = 2
+	mul.Timeouts = map[string]time.Duration{"A1": time.Second}
+
+	res, err := mul.Collect(&Task{Data: "example.com", DataType: "domain"})
+	if err != nil {
+		t.Fatalf("MultiRun.Collect returned error: %v", err)
+	}
+	if len(res.Reports) != 4 {
+		t.Errorf("got %d reports, want 4", len(res.Reports))
+	}
+	if peak != 2 {
+		t.Errorf("%d analyzers were running at once, want 2", peak)
+	}
+}
@@ -0,0 +1,107 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// AnalyzerFunc analyzes the job input and returns a report. The context is
+// cancelled when the job timeout from the config passes.
+type AnalyzerFunc func(context.Context, *JobInput) (*AnalyzerReport, error)
+
+// RunAnalyzer is an analyzer entry point: it reads the job input from
+// DefaultInput, runs fn and writes the report to DefaultOutput. If a Cortex 3
+// job directory is detected by DetectJobDir, it's used instead. It's the only
+// place the analyzer exits: with 0 on success and 1 otherwise. The error is
+// also printed to stderr, since it may fail before any report is written.
+func RunAnalyzer(fn AnalyzerFunc) {
+	var err error
+	if dir := DetectJobDir(); dir != "" {
+		err = RunAnalyzerInDir(context.Background(), dir, fn)
+	} else {
+		err = RunAnalyzerWith(context.Background(), DefaultInput, DefaultOutput, fn)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// RunAnalyzerWith reads the job input from r, checks its TLP and PAP, runs fn
+// and writes its report to w. A failed check, an error or a panic of fn is
+// written to w as AnalyzerError and returned.
+func RunAnalyzerWith(ctx context.Context, r io.Reader, w io.Writer, fn AnalyzerFunc) error {
+	return runAnalyzer(ctx, r, w, "", fn)
+}
+
+// runAnalyzer runs fn with the input bound to the job directory dir, which
+// is empty for stdin and stdout
+func runAnalyzer(ctx context.Context, r io.Reader, w io.Writer, dir string, fn AnalyzerFunc) error {
+	in, err := parseInput(r)
+	if err != nil {
+		err = fmt.Errorf("can't parse input: %w", err)
+		if werr := writeJSON(w, &AnalyzerError{ErrorMessage: err.Error()}); werr != nil {
+			return werr
+		}
+		return err
+	}
+	in.setDir(dir)
+
+	report, err := in.analyze(ctx, fn)
+	if err == nil {
+		err = in.completeReport(report)
+	}
+	if err != nil {
+		if werr := in.WriteError(w, err); werr != nil {
+			return werr
+		}
+		return err
+	}
+
+	return writeJSON(w, report)
+}
+
+// analyze checks TLP and PAP and runs fn within the job timeout
+func (j *JobInput) analyze(ctx context.Context, fn AnalyzerFunc) (*AnalyzerReport, error) {
+	if err := j.Config.checkSharing(j.TLP, j.PAP); err != nil {
+		return nil, err
+	}
+
+	if minutes, err := j.Config.GetFloat("jobTimeout"); err == nil && minutes > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(minutes*float64(time.Minute)))
+		defer cancel()
+	}
+
+	type result struct {
+		report *AnalyzerReport
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{err: fmt.Errorf("analyzer panicked: %v", p)}
+			}
+		}()
+
+		report, err := fn(ctx, j)
+		if err == nil && report == nil {
+			report = &AnalyzerReport{}
+		}
+		done <- result{report, err}
+	}()
+
+	// fn may ignore the context, so don't wait for it after the timeout
+	select {
+	case res := <-done:
+		return res.report, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("analyzer didn't finish in time: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,100 @@
+package cortex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunAnalyzerWith(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+
+	var tests = []struct {
+		name  string
+		input string
+		fn    AnalyzerFunc
+		err   string
+	}{
+		{
+			"success",
+			string(sampleConfig),
+			func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+				return in.NewReport(map[string]string{"hash": in.Data}, []Taxonomy{{Level: TxSafe}})
+			},
+			"",
+		},
+		{
+			"error",
+			string(sampleConfig),
+			func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+				return nil, errLookup
+			},
+			errLookup.Error(),
+		},
+		{
+			"panic",
+			string(sampleConfig),
+			func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+				var m map[string]int
+				m["boom"]++
+				return nil, nil
+			},
+			"analyzer panicked",
+		},
+		{
+			"sharing",
+			`{"dataType":"ip","tlp":3,"config":{"check_tlp":true,"max_tlp":2}}`,
+			func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+				t.Error("analyzer is run despite TLP")
+				return nil, nil
+			},
+			errTooHighTLP.Error(),
+		},
+		{
+			"timeout",
+			`{"dataType":"ip","config":{"jobTimeout":0.0001}}`,
+			func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+				select {}
+			},
+			"didn't finish in time",
+		},
+		{
+			"input",
+			`{"dataType":`,
+			nil,
+			"can't parse input",
+		},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := RunAnalyzerWith(context.Background(), strings.NewReader(tt.input), &out, tt.fn)
+
+		var got struct {
+			Success      bool            `json:"success"`
+			ErrorMessage string          `json:"errorMessage"`
+			Full         json.RawMessage `json:"full"`
+			Summary      *Summary        `json:"summary"`
+		}
+		if jerr := json.Unmarshal(out.Bytes(), &got); jerr != nil {
+			t.Fatalf("%s: invalid output %q: %v", tt.name, out.String(), jerr)
+		}
+
+		if tt.err == "" {
+			if err != nil || !got.Success || string(got.Full) != `{"hash":"d41d8cd98f00b204e9800998ecf8427e"}` {
+				t.Errorf("%s: got %s, %v", tt.name, out.String(), err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: RunAnalyzerWith returned %v, want %q", tt.name, err, tt.err)
+		}
+		if got.Success || got.ErrorMessage != err.Error() {
+			t.Errorf("%s: got %s, want error report", tt.name, out.String())
+		}
+	}
+}
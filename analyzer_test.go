@@ -2,6 +2,7 @@ package cortex
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"reflect"
 	"testing"
@@ -25,6 +26,35 @@ func TestListAnalyzers(t *testing.T) {
 	}
 }
 
+func TestListAnalyzersPaged(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+	client.PageSize = 1
+
+	var ranges []string
+	mux.HandleFunc("/"+analyzersURL, func(w http.ResponseWriter, r *http.Request) {
+		rng := r.URL.Query().Get("range")
+		ranges = append(ranges, rng)
+
+		if rng == "0-1" {
+			w.Write(analyzersJSON)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	got, _, err := client.Analyzers.List(context.Background())
+	if err != nil {
+		t.Errorf("Analyzer.List returned error: %v", err)
+	}
+	if want := wantList; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyzer.List = %+v, want %+v", got, want)
+	}
+	if want := []string{"0-1", "1-2"}; !reflect.DeepEqual(ranges, want) {
+		t.Errorf("requested ranges = %v, want %v", ranges, want)
+	}
+}
+
 func TestListByTypeAnalyzers(t *testing.T) {
 	client, mux, _, closer := setup()
 	defer closer()
@@ -61,6 +91,128 @@ func TestDataTypes(t *testing.T) {
 	}
 }
 
+func TestAnalyzerDefinitions(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+analyzerDefinitionsURL, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(definitionsJSON)
+	})
+
+	got, _, err := client.Analyzers.Definitions(context.Background())
+	if err != nil {
+		t.Errorf("Analyzer.Definitions returned error: %v", err)
+	}
+	if want := wantDefinitions; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyzer.Definitions = %+v, want %+v", got, want)
+	}
+}
+
+func TestEnableAnalyzer(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+organizationAnalyzerURL+"/MaxMind_GeoIP_3_0", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"name":          "MaxMind_GeoIP_3_0",
+			"configuration": map[string]interface{}{"check_tlp": true},
+			"rate":          float64(10),
+			"rateUnit":      "Minute",
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("enable body = %+v, want %+v", body, want)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(wantList[0])
+	})
+
+	got, _, err := client.Analyzers.Enable(context.Background(), "MaxMind_GeoIP_3_0", &Analyzer{
+		Configuration: map[string]interface{}{"check_tlp": true},
+		Rate:          10,
+		RateUnit:      "Minute",
+	})
+	if err != nil {
+		t.Errorf("Analyzer.Enable returned error: %v", err)
+	}
+	if want := &wantList[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyzer.Enable = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateAnalyzer(t *testing.T) {
+	var tests = []struct {
+		update *AnalyzerUpdate
+		body   map[string]interface{}
+	}{
+		{
+			&AnalyzerUpdate{Configuration: map[string]interface{}{"check_tlp": false}},
+			map[string]interface{}{"configuration": map[string]interface{}{"check_tlp": false}},
+		},
+		{
+			&AnalyzerUpdate{Rate: new(int), RateUnit: "Minute"},
+			map[string]interface{}{"rate": nil, "rateUnit": nil},
+		},
+	}
+
+	for i, tt := range tests {
+		client, mux, _, closer := setup()
+
+		mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PATCH" {
+				t.Errorf("%d: method = %s, want PATCH", i, r.Method)
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(body, tt.body) {
+				t.Errorf("%d: update body = %+v, want %+v", i, body, tt.body)
+			}
+
+			json.NewEncoder(w).Encode(wantList[0])
+		})
+
+		got, _, err := client.Analyzers.Update(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", tt.update)
+		if err != nil {
+			t.Errorf("%d: Analyzer.Update returned error: %v", i, err)
+		}
+		if want := &wantList[0]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: Analyzer.Update = %+v, want %+v", i, got, want)
+		}
+
+		closer()
+	}
+}
+
+func TestDisableAnalyzer(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var disabled bool
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4", func(w http.ResponseWriter, r *http.Request) {
+		disabled = r.Method == "DELETE"
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.Analyzers.Disable(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4"); err != nil {
+		t.Errorf("Analyzer.Disable returned error: %v", err)
+	}
+	if !disabled {
+		t.Error("the analyzer is not disabled")
+	}
+}
+
 var analyzerType = "ip"
 
 var analyzersJSON = []byte(`
@@ -133,3 +285,52 @@ var wantList = []Analyzer{
 }
 
 var dataTypes = []string{"ip"}
+
+var definitionsJSON = []byte(`
+[
+  {
+    "id": "MaxMind_GeoIP_3_0",
+    "name": "MaxMind_GeoIP",
+    "version": "3.0",
+    "description": "Use MaxMind to geolocate an IP address.",
+    "author": "CERT-BDF",
+    "url": "https://github.com/TheHive-Project/Cortex-Analyzers",
+    "license": "AGPL-V3",
+    "baseConfig": "MaxMind",
+    "dataTypeList": ["ip"],
+    "configurationItems": [
+      {
+        "name": "key",
+        "description": "API key",
+        "type": "string",
+        "multi": false,
+        "required": true
+      }
+    ],
+    "command": "MaxMind/geo.py"
+  }
+]
+`)
+
+var wantDefinitions = []AnalyzerDefinition{
+	{
+		ID:           "MaxMind_GeoIP_3_0",
+		Name:         "MaxMind_GeoIP",
+		Version:      "3.0",
+		Description:  "Use MaxMind to geolocate an IP address.",
+		Author:       "CERT-BDF",
+		URL:          "https://github.com/TheHive-Project/Cortex-Analyzers",
+		License:      "AGPL-V3",
+		BaseConfig:   "MaxMind",
+		DataTypeList: []string{"ip"},
+		ConfigurationItems: []ConfigurationItem{
+			{
+				Name:        "key",
+				Description: "API key",
+				Type:        "string",
+				Required:    true,
+			},
+		},
+		Command: "MaxMind/geo.py",
+	},
+}
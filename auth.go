@@ -1,7 +1,41 @@
 package cortex
 
-type auth interface {
-	Token() string
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const (
+	loginURL = APIRoute + "/login"
+
+	csrfCookie = "CORTEX-XSRF-TOKEN"
+	csrfHeader = "X-CORTEX-XSRF-TOKEN"
+
+	// DefaultUserHeader is a header used by Impersonation by default. It
+	// should match auth.header.name setting of Cortex.
+	DefaultUserHeader = "X-Remote-User"
+)
+
+// Authenticator authenticates requests to Cortex API
+type Authenticator interface {
+	Authenticate(*http.Request) error
+}
+
+// clientBinder is implemented by authenticators that need the client, e.g.
+// to log in before the first request
+type clientBinder interface {
+	bind(*Client)
+}
+
+// expirer is implemented by authenticators that can re-authenticate when
+// Cortex responds with 401 Unauthorized. expire reports whether there was
+// anything to renew, i.e. whether the request is worth resending.
+type expirer interface {
+	expire() bool
 }
 
 // APIAuth represents authentication by API token
@@ -9,7 +43,176 @@ type APIAuth struct {
 	APIKey string
 }
 
-// Token returns API key and satisfies auth interface
+// Token returns API key as an Authorization header value
 func (a *APIAuth) Token() string {
 	return "Bearer " + a.APIKey
 }
+
+// Authenticate sets Authorization header and satisfies Authenticator
+// interface
+func (a *APIAuth) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", a.Token())
+	return nil
+}
+
+// BasicAuth represents HTTP basic authentication, that should be enabled
+// in Cortex with auth.method.basic setting
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets basic auth credentials and satisfies Authenticator
+// interface
+func (a *BasicAuth) Authenticate(r *http.Request) error {
+	r.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// SessionAuth represents authentication by username and password. It logs
+// in using /api/login before the first request and keeps the session cookie
+// and CSRF token for the next ones. It logs in again once the session is
+// expired.
+type SessionAuth struct {
+	Username string
+	Password string
+
+	mu      sync.Mutex
+	client  *Client
+	cookies []*http.Cookie
+	csrf    string
+}
+
+func (a *SessionAuth) bind(c *Client) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.client = c
+}
+
+func (a *SessionAuth) expire() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cookies = nil
+	a.csrf = ""
+	return true
+}
+
+// Authenticate sets session cookies and CSRF token header, it logs in if
+// there is no session yet. Satisfies Authenticator interface.
+func (a *SessionAuth) Authenticate(r *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cookies == nil {
+		if err := a.login(r); err != nil {
+			return err
+		}
+	}
+
+	// the request could be resent with an expired session
+	r.Header.Del("Cookie")
+	for _, c := range a.cookies {
+		r.AddCookie(c)
+	}
+	if a.csrf != "" {
+		r.Header.Set(csrfHeader, a.csrf)
+	}
+
+	return nil
+}
+
+// login requests a new session using context of r
+func (a *SessionAuth) login(r *http.Request) error {
+	if a.client == nil {
+		return errors.New("session auth is not bound to a client")
+	}
+
+	u, err := a.client.BaseURL.Parse(loginURL)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"user":     a.Username,
+		"password": a.Password,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(r.Context())
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("Accept", mediaType)
+	if a.client.UserAgent != "" {
+		req.Header.Set("User-Agent", a.client.UserAgent)
+	}
+
+	resp, err := a.client.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	cookies := make([]*http.Cookie, 0)
+	for _, c := range resp.Cookies() {
+		if c.Name == csrfCookie {
+			a.csrf = c.Value
+		}
+		cookies = append(cookies, c)
+	}
+	a.cookies = cookies
+
+	return nil
+}
+
+// Impersonation wraps an Authenticator and runs requests on behalf of
+// another user, possibly of another organization, by setting a user header.
+// Cortex trusts the header only if header authentication is enabled.
+type Impersonation struct {
+	Authenticator
+
+	// User is a login of the user to act as
+	User string
+
+	// Header defaults to DefaultUserHeader
+	Header string
+}
+
+// Authenticate authenticates the request with the wrapped Authenticator and
+// sets the user header
+func (i *Impersonation) Authenticate(r *http.Request) error {
+	if i.Authenticator != nil {
+		if err := i.Authenticator.Authenticate(r); err != nil {
+			return err
+		}
+	}
+
+	h := i.Header
+	if h == "" {
+		h = DefaultUserHeader
+	}
+	r.Header.Set(h, i.User)
+
+	return nil
+}
+
+func (i *Impersonation) bind(c *Client) {
+	if b, ok := i.Authenticator.(clientBinder); ok {
+		b.bind(c)
+	}
+}
+
+func (i *Impersonation) expire() bool {
+	if e, ok := i.Authenticator.(expirer); ok {
+		return e.expire()
+	}
+	return false
+}
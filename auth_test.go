@@ -2,9 +2,10 @@ package cortex
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
 	"testing"
 )
 
@@ -21,10 +22,128 @@ func TestUnauthorized(t *testing.T) {
 	if got == nil {
 		t.Error("test request Users.Current hasn't returned an error")
 	}
-	if want := errUnauthorized; !reflect.DeepEqual(got, want) {
+	if want := errUnauthorized; got.Error() != want.Error() {
 		t.Errorf("error %+v, want %+v", got, want)
 	}
+	if !IsAuthError(got) {
+		t.Errorf("IsAuthError(%v) = false, want true", got)
+	}
+
+	var er *ErrorResponse
+	if !errors.As(got, &er) {
+		t.Fatalf("error %T is not an *ErrorResponse", got)
+	}
+	if er.StatusCode != http.StatusUnauthorized || er.Type != "AuthenticationError" {
+		t.Errorf("ErrorResponse = %+v, want 401 AuthenticationError", er)
+	}
+}
+
+func TestSessionAuth(t *testing.T) {
+	client, mux, serverURL, closer := setup()
+	defer closer()
+
+	sa := &SessionAuth{Username: "analyst", Password: "secret"}
+	client, _ = NewClient(serverURL+"/", &ClientOpts{Auth: sa})
+
+	var logins int
+	mux.HandleFunc("/"+loginURL, func(w http.ResponseWriter, r *http.Request) {
+		var creds map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			t.Fatal(err)
+		}
+		if creds["user"] != "analyst" || creds["password"] != "secret" {
+			t.Errorf("login credentials = %+v", creds)
+		}
+
+		logins++
+		session := fmt.Sprintf("session-%d", logins)
+		http.SetCookie(w, &http.Cookie{Name: "CORTEX_SESSION", Value: session})
+		http.SetCookie(w, &http.Cookie{Name: csrfCookie, Value: "csrf-" + session})
+		w.Write(userJSON)
+	})
 
+	var calls int
+	mux.HandleFunc("/"+currentUser, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		c, err := r.Cookie("CORTEX_SESSION")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := r.Header.Get(csrfHeader), "csrf-"+c.Value; got != want {
+			t.Errorf("CSRF header = %s, want %s", got, want)
+		}
+
+		// expire the first session after the first call
+		if c.Value == "session-1" && calls > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write(unauthorizedJSON)
+			return
+		}
+		w.Write(userJSON)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Users.Current(context.Background()); err != nil {
+			t.Fatalf("Users.Current returned error: %v", err)
+		}
+	}
+
+	if logins != 2 {
+		t.Errorf("logged in %d times, want 2", logins)
+	}
+}
+
+func TestImpersonation(t *testing.T) {
+	client, mux, serverURL, closer := setup()
+	defer closer()
+
+	client, _ = NewClient(serverURL+"/", &ClientOpts{
+		Auth: &Impersonation{
+			Authenticator: &APIAuth{APIKey: "dummy-key"},
+			User:          "analyst",
+		},
+	})
+
+	mux.HandleFunc("/"+currentUser, func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get(DefaultUserHeader), "analyst"; got != want {
+			t.Errorf("user header = %s, want %s", got, want)
+		}
+		if got, want := r.Header.Get("Authorization"), "Bearer dummy-key"; got != want {
+			t.Errorf("Authorization header = %s, want %s", got, want)
+		}
+		w.Write(userJSON)
+	})
+
+	if _, _, err := client.Users.Current(context.Background()); err != nil {
+		t.Fatalf("Users.Current returned error: %v", err)
+	}
+}
+
+func TestImpersonationUnauthorized(t *testing.T) {
+	client, mux, serverURL, closer := setup()
+	defer closer()
+
+	client, _ = NewClient(serverURL+"/", &ClientOpts{
+		Auth: &Impersonation{
+			Authenticator: &APIAuth{APIKey: "dummy-key"},
+			User:          "analyst",
+		},
+	})
+
+	var requested int
+	mux.HandleFunc("/"+currentUser, func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write(unauthorizedJSON)
+	})
+
+	if _, _, err := client.Users.Current(context.Background()); !IsAuthError(err) {
+		t.Errorf("Users.Current returned %v, want %v", err, ErrAuthentication)
+	}
+	// an API key can't be renewed, so the request isn't resent
+	if requested != 1 {
+		t.Errorf("user is requested %d times, want once", requested)
+	}
 }
 
 var (
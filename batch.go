@@ -0,0 +1,253 @@
+package cortex
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultBatchWorkers = 4
+
+// BatchOpts configures AnalyzerService.RunBatch
+type BatchOpts struct {
+	// Workers is a number of observables analyzed at once, defaults to 4
+	Workers int
+
+	// Timeout to wait for each report
+	Timeout time.Duration
+
+	// MultiRun is a template for analyzing each observable, e.g. to filter
+	// analyzers or limit in-flight jobs, its context and timeout are
+	// replaced with RunBatch ones
+	MultiRun *MultiRun
+
+	// Checkpoint is a path to a JSON lines file with finished observables.
+	// If the file exists, observables recorded there are not analyzed
+	// again, so an interrupted batch can be resumed. Only observables with
+	// reports and without failed analyzers are recorded, the others are
+	// analyzed again on resume.
+	Checkpoint string
+
+	// OnProgress is called after each observable is finished, calls are
+	// never concurrent
+	OnProgress func(*BatchProgress)
+}
+
+// BatchResult represents results of all analyzers for a single observable
+type BatchResult struct {
+	// Index of the observable in the batch
+	Index      int
+	Observable Observable
+	Result     *MultiResult
+
+	// Err is set if the observable wasn't analyzed at all
+	Err error
+
+	// Resumed is true if the result was restored from the checkpoint
+	Resumed bool
+}
+
+// BatchProgress is a progress event of RunBatch
+type BatchProgress struct {
+	Done   int
+	Failed int
+	Total  int
+	Last   *BatchResult
+}
+
+// batchRecord is a line of the checkpoint file
+type batchRecord struct {
+	Index     int                      `json:"index"`
+	DataType  string                   `json:"dataType"`
+	Data      string                   `json:"data"`
+	Reports   []*Report                `json:"reports,omitempty"`
+	Durations map[string]time.Duration `json:"durations,omitempty"`
+	Verdict   TaxonomyLevel            `json:"verdict,omitempty"`
+}
+
+// RunBatch analyzes many observables with all appropriate analyzers using
+// a pool of workers. Analyzers are listed once for the whole batch. Results
+// are returned in the order of the observables. If the context is cancelled,
+// results collected so far are returned with the context error.
+func (a *AnalyzerServiceOp) RunBatch(ctx context.Context, obs []Observable, opts *BatchOpts) ([]*BatchResult, error) {
+	if opts == nil {
+		opts = &BatchOpts{}
+	}
+
+	results := make([]*BatchResult, len(obs))
+	var cp *os.File
+	if opts.Checkpoint != "" {
+		if err := readCheckpoint(opts.Checkpoint, obs, results); err != nil {
+			return nil, err
+		}
+
+		var err error
+		cp, err = os.OpenFile(opts.Checkpoint, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		defer cp.Close()
+	}
+
+	all, _, err := a.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[string][]Analyzer)
+	for _, an := range all {
+		for _, t := range an.DataTypeList {
+			byType[t] = append(byType[t], an)
+		}
+	}
+
+	m := a.NewMultiRun(ctx, opts.Timeout)
+	if opts.MultiRun != nil {
+		mc := *opts.MultiRun
+		mc.aso, mc.ctx, mc.Timeout = a, ctx, opts.Timeout
+		m = &mc
+	}
+
+	var (
+		mu       sync.Mutex
+		progress = &BatchProgress{Total: len(obs)}
+		cpErr    error
+	)
+	finish := func(br *BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		results[br.Index] = br
+		progress.Done++
+		if br.Err != nil {
+			progress.Failed++
+		}
+		progress.Last = br
+
+		if cp != nil && !br.Resumed && completed(br) && cpErr == nil {
+			cpErr = writeCheckpoint(cp, br)
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+
+	idx := make(chan int)
+	go func() {
+		defer close(idx)
+		for i := range obs {
+			if results[i] != nil {
+				finish(results[i])
+				continue
+			}
+			select {
+			case idx <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultBatchWorkers
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				res, err := m.collect(obs[i], byType[obs[i].Type()])
+				if err == nil {
+					err = ctx.Err()
+				}
+				finish(&BatchResult{Index: i, Observable: obs[i], Result: res, Err: err})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+
+	return results, cpErr
+}
+
+// readCheckpoint restores results of the observables recorded in the
+// checkpoint file. A record is only used if the observable at its index is
+// the same, so a changed input is analyzed again.
+func readCheckpoint(path string, obs []Observable, results []*BatchResult) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 64<<20)
+	for s.Scan() {
+		var rec batchRecord
+		// the last line may be truncated if the batch was killed
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			continue
+		}
+
+		if rec.Index < 0 || rec.Index >= len(obs) {
+			continue
+		}
+		o := obs[rec.Index]
+		if o.Type() != rec.DataType || o.Description() != rec.Data {
+			continue
+		}
+
+		res := &MultiResult{
+			Reports:   rec.Reports,
+			Errors:    make(map[string]error),
+			Durations: rec.Durations,
+			Verdict:   rec.Verdict,
+		}
+		results[rec.Index] = &BatchResult{
+			Index:      rec.Index,
+			Observable: o,
+			Result:     res,
+			Resumed:    true,
+		}
+	}
+
+	return s.Err()
+}
+
+// completed reports whether all analyzers have succeeded for the
+// observable, so it needn't be analyzed again
+func completed(br *BatchResult) bool {
+	return br.Err == nil && br.Result != nil && len(br.Result.Reports) > 0 && len(br.Result.Errors) == 0
+}
+
+// writeCheckpoint appends a finished observable to the checkpoint file
+func writeCheckpoint(f *os.File, br *BatchResult) error {
+	rec := batchRecord{
+		Index:     br.Index,
+		DataType:  br.Observable.Type(),
+		Data:      br.Observable.Description(),
+		Reports:   br.Result.Reports,
+		Durations: br.Result.Durations,
+		Verdict:   br.Result.Verdict,
+	}
+
+	b, err := json.Marshal(&rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,133 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var (
+		mu      sync.Mutex
+		lists   int
+		started = make(map[string]int)
+	)
+	mux.HandleFunc("/"+analyzersURL, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lists++
+		mu.Unlock()
+		w.Write([]byte(`[
+			{"id":"maxmind","name":"MaxMind_GeoIP_3_0","dataTypeList":["ip"]},
+			{"id":"vt","name":"VirusTotal_GetReport_3_0","dataTypeList":["ip","domain"]}
+		]`))
+	})
+	for _, id := range []string{"maxmind", "vt"} {
+		id := id
+		mux.HandleFunc("/"+analyzersURL+"/"+id+"/run", func(w http.ResponseWriter, r *http.Request) {
+			var task Task
+			if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			started[task.Data]++
+			// VirusTotal is unavailable for 1.1.1.1 during the first run
+			unavailable := id == "vt" && task.Data == "1.1.1.1" && started[task.Data] <= 2
+			mu.Unlock()
+			if unavailable {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, `{"id":"%s-%s"}`, id, task.Data)
+		})
+	}
+	mux.HandleFunc("/"+jobsURL+"/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"+jobsURL+"/"), "/waitreport")
+		level := "safe"
+		if strings.HasSuffix(id, "evil.com") {
+			level = "malicious"
+		}
+		fmt.Fprintf(w, `{"id":"%s","report":{"success":true,"summary":{"taxonomies":[{"level":"%s"}]}}}`, id, level)
+	})
+
+	obs := []Observable{
+		&Task{Data: "1.1.1.1", DataType: "ip"},
+		&Task{Data: "evil.com", DataType: "domain"},
+		&Task{Data: "8.8.8.8", DataType: "ip"},
+		&Task{Data: "example.com", DataType: "domain"},
+	}
+
+	dir, err := os.MkdirTemp("", "go-cortex-batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint.jsonl")
+
+	var events []BatchProgress
+	opts := &BatchOpts{
+		Workers:    2,
+		Timeout:    time.Minute,
+		Checkpoint: checkpoint,
+		OnProgress: func(p *BatchProgress) {
+			events = append(events, *p)
+		},
+	}
+
+	// the first run is interrupted after two observables
+	results, err := client.Analyzers.RunBatch(context.Background(), obs[:2], opts)
+	if err != nil {
+		t.Fatalf("RunBatch returned error: %v", err)
+	}
+	if len(results) != 2 || len(results[0].Result.Errors) != 1 || results[1].Result.Verdict != TxMalicious {
+		t.Errorf("RunBatch returned unexpected results %+v", results)
+	}
+
+	events = nil
+	results, err = client.Analyzers.RunBatch(context.Background(), obs, opts)
+	if err != nil {
+		t.Fatalf("RunBatch returned error: %v", err)
+	}
+
+	for i, r := range results {
+		if r.Index != i || r.Observable != obs[i] || r.Err != nil || len(r.Result.Errors) != 0 {
+			t.Errorf("%d: unexpected result %+v", i, r)
+		}
+		// the observable with the failed analyzer is analyzed again
+		if r.Resumed != (i == 1) {
+			t.Errorf("%d: Resumed = %t, want %t", i, r.Resumed, i == 1)
+		}
+	}
+	if v := results[1].Result.Verdict; v != TxMalicious {
+		t.Errorf("resumed verdict = %s, want %s", v, TxMalicious)
+	}
+	if v := results[3].Result.Verdict; v != TxSafe {
+		t.Errorf("verdict = %s, want %s", v, TxSafe)
+	}
+
+	for data, n := range started {
+		want := map[string]int{"1.1.1.1": 4, "8.8.8.8": 2}[data]
+		if strings.HasSuffix(data, ".com") {
+			want = 1
+		}
+		if n != want {
+			t.Errorf("%s is analyzed %d times, want %d", data, n, want)
+		}
+	}
+	if lists != 2 {
+		t.Errorf("analyzers are listed %d times, want once per batch", lists)
+	}
+	if len(events) != 4 || events[3].Done != 4 || events[3].Total != 4 {
+		t.Errorf("unexpected progress events %+v", events)
+	}
+}
@@ -0,0 +1,237 @@
+package cortex
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CacheOpts configures reuse of fresh successful reports by AnalyzerService
+// Run and MultiRun instead of starting new jobs for the same observable.
+// File observables are never cached.
+type CacheOpts struct {
+	// MaxAge is a maximum age of a report to be reused, zero means reports
+	// never expire both in the LRU cache and in Cortex search
+	MaxAge time.Duration
+
+	// Size is a number of reports kept in the in-process LRU cache,
+	// zero disables it
+	Size int
+
+	// Search enables searching Cortex for a recent successful job with
+	// the same analyzer, data type, data and TLP
+	Search bool
+}
+
+// reportCache reuses reports and deduplicates concurrent runs of the same
+// analyzer on the same observable
+type reportCache struct {
+	opts *CacheOpts
+
+	mu       sync.Mutex
+	ll       *list.List
+	items    map[string]*list.Element
+	inflight map[string]*inflightRun
+}
+
+type cacheEntry struct {
+	key     string
+	report  *Report
+	created time.Time
+}
+
+// inflightRun is a run shared by concurrent callers. It isn't bound to any
+// caller's context and is cancelled only when all of them have gone.
+type inflightRun struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	report  *Report
+	err     error
+}
+
+func newReportCache(opts *CacheOpts) *reportCache {
+	return &reportCache{
+		opts:     opts,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		inflight: make(map[string]*inflightRun),
+	}
+}
+
+// cacheKey is a hash of the analyzer ID and the observable
+func cacheKey(id string, t *Task) string {
+	var tlp string
+	if t.TLP != nil {
+		tlp = fmt.Sprint(*t.TLP)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", id, t.DataType, t.Data, tlp)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// do returns a cached report or a report found in Cortex if any, otherwise
+// it calls run. Concurrent calls with the same key share a single run, which
+// gets its own context, so a caller that gives up doesn't fail the others.
+func (c *reportCache) do(ctx context.Context, a *AnalyzerServiceOp, id string, t *Task, run func(context.Context) (*Report, error)) (*Report, error) {
+	key := cacheKey(id, t)
+
+	c.mu.Lock()
+	if r, ok := c.get(key); ok {
+		c.mu.Unlock()
+		return r, nil
+	}
+	in, ok := c.inflight[key]
+	if !ok {
+		in = c.start(key, a, id, t, run)
+	}
+	in.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-in.done:
+		return in.report, in.err
+	case <-ctx.Done():
+		c.leave(key, in)
+		return nil, ctx.Err()
+	}
+}
+
+// start runs the search and run in background for the key, c.mu must be held
+func (c *reportCache) start(key string, a *AnalyzerServiceOp, id string, t *Task, run func(context.Context) (*Report, error)) *inflightRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := &inflightRun{done: make(chan struct{}), cancel: cancel}
+	c.inflight[key] = in
+
+	go func() {
+		defer cancel()
+
+		var r *Report
+		var err error
+		if c.opts.Search {
+			r, err = c.search(ctx, a, id, t)
+		}
+		if err != nil || r == nil {
+			r, err = run(ctx)
+		}
+
+		c.mu.Lock()
+		if c.inflight[key] == in {
+			delete(c.inflight, key)
+		}
+		if err == nil && r != nil && r.ReportBody.Success {
+			c.add(key, r)
+		}
+		in.report, in.err = r, err
+		c.mu.Unlock()
+		close(in.done)
+	}()
+
+	return in
+}
+
+// leave unsubscribes a waiter from the run and cancels it if nobody waits
+// for it anymore
+func (c *reportCache) leave(key string, in *inflightRun) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	in.waiters--
+	if in.waiters > 0 {
+		return
+	}
+
+	// new callers shouldn't join the cancelled run
+	if c.inflight[key] == in {
+		delete(c.inflight, key)
+	}
+	in.cancel()
+}
+
+// search finds the most recent successful job in Cortex and returns its
+// report, it returns nil report if no job is found
+func (c *reportCache) search(ctx context.Context, a *AnalyzerServiceOp, id string, t *Task) (*Report, error) {
+	qs := []Query{
+		Eq("analyzerId", id),
+		Eq("dataType", t.DataType),
+		Eq("data", t.Data),
+		Eq("status", "Success"),
+	}
+	if c.opts.MaxAge > 0 {
+		since := time.Now().Add(-c.opts.MaxAge).UnixNano() / int64(time.Millisecond)
+		qs = append(qs, Gte("createdAt", since))
+	}
+	if t.TLP != nil {
+		qs = append(qs, Eq("tlp", *t.TLP))
+	}
+
+	jso := &JobServiceOp{a.client}
+	jobs, _, err := jso.Search(ctx, &SearchOpts{
+		Query: And(qs...),
+		Range: "0-1",
+		Sort:  []string{"-createdAt"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	r, _, err := jso.GetReport(ctx, jobs[0].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// get returns a fresh report from the LRU cache, c.mu must be held
+func (c *reportCache) get(key string) (*Report, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*cacheEntry)
+	if c.opts.MaxAge > 0 && time.Since(e.created) > c.opts.MaxAge {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.report, true
+}
+
+// add puts the report to the LRU cache evicting the oldest one if needed,
+// c.mu must be held
+func (c *reportCache) add(key string, r *Report) {
+	if c.opts.Size <= 0 {
+		return
+	}
+
+	// the report age is counted from the job creation
+	created := time.Now()
+	if r.CreatedAt > 0 {
+		created = time.Unix(0, r.CreatedAt*int64(time.Millisecond))
+	}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = &cacheEntry{key, r, created}
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&cacheEntry{key, r, created})
+	if c.ll.Len() > c.opts.Size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
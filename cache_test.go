@@ -0,0 +1,186 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestReportCache(t *testing.T) {
+	var tests = []struct {
+		opts    *CacheOpts
+		started int
+	}{
+		{&CacheOpts{MaxAge: time.Hour, Size: 10}, 1},
+		{&CacheOpts{MaxAge: time.Hour, Search: true}, 0},
+		{&CacheOpts{Search: true}, 0},
+		{&CacheOpts{MaxAge: time.Hour}, 2},
+	}
+
+	for _, tt := range tests {
+		client, mux, serverURL, closer := setup()
+		client, _ = NewClient(serverURL+"/", &ClientOpts{
+			Auth:  &APIAuth{APIKey: "dummy-key"},
+			Cache: tt.opts,
+		})
+
+		var started int
+		mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+			started++
+			w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/waitreport", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(cachedReportJSON)
+		})
+		mux.HandleFunc("/"+jobsSearchURL, func(w http.ResponseWriter, r *http.Request) {
+			var opts SearchOpts
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				t.Fatal(err)
+			}
+			// zero MaxAge doesn't limit the job creation time
+			clauses := 5
+			if tt.opts.MaxAge == 0 {
+				clauses = 4
+			}
+			if opts.Range != "0-1" || len(opts.Query["_and"].([]interface{})) != clauses {
+				t.Errorf("unexpected search %+v", opts)
+			}
+			w.Write([]byte(`[{"id":"AWOsZ3pPqNgGAnpM4Ui1"}]`))
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui1/report", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(cachedReportJSON)
+		})
+
+		aso := client.Analyzers.(*AnalyzerServiceOp)
+		for i := 0; i < 2; i++ {
+			r, err := aso.run(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", &Task{
+				Data:     "1.1.1.1",
+				DataType: "ip",
+			}, time.Minute)
+			if err != nil {
+				t.Fatalf("%+v: run returned error: %v", tt.opts, err)
+			}
+			if !r.ReportBody.Success {
+				t.Errorf("%+v: got unsuccessful report %+v", tt.opts, r)
+			}
+		}
+
+		if started != tt.started {
+			t.Errorf("%+v: started %d jobs, want %d", tt.opts, started, tt.started)
+		}
+
+		closer()
+	}
+}
+
+func TestReportCacheEviction(t *testing.T) {
+	c := newReportCache(&CacheOpts{MaxAge: time.Hour, Size: 2})
+
+	for _, k := range []string{"a", "b", "c"} {
+		c.add(k, &Report{})
+	}
+
+	if _, ok := c.get("a"); ok {
+		t.Error("the oldest report is not evicted")
+	}
+	for _, k := range []string{"b", "c"} {
+		if _, ok := c.get(k); !ok {
+			t.Errorf("report %s is evicted", k)
+		}
+	}
+
+	c.add("d", &Report{Job: Job{CreatedAt: time.Now().Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)}})
+	if _, ok := c.get("d"); ok {
+		t.Error("expired report is returned")
+	}
+}
+
+func TestReportCacheWaiters(t *testing.T) {
+	c := newReportCache(&CacheOpts{Size: 1})
+	task := &Task{Data: "1.1.1.1", DataType: "ip"}
+	key := cacheKey("analyzer", task)
+
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+	run := func(ctx context.Context) (*Report, error) {
+		select {
+		case <-release:
+			return &Report{ReportBody: ReportBody{Success: true}}, nil
+		case <-ctx.Done():
+			close(cancelled)
+			return nil, ctx.Err()
+		}
+	}
+	waiters := func(n int) {
+		for {
+			c.mu.Lock()
+			in, ok := c.inflight[key]
+			joined := ok && in.waiters == n
+			c.mu.Unlock()
+			if joined {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.do(ctx, nil, "analyzer", task, run)
+		first <- err
+	}()
+	waiters(1)
+
+	second := make(chan *Report, 1)
+	go func() {
+		r, err := c.do(context.Background(), nil, "analyzer", task, func(context.Context) (*Report, error) {
+			t.Error("concurrent call started another run")
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("second caller got error: %v", err)
+		}
+		second <- r
+	}()
+	waiters(2)
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("first caller got %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if r := <-second; r == nil || !r.ReportBody.Success {
+		t.Errorf("second caller got %+v, want the shared report", r)
+	}
+
+	// the run is cancelled when its only caller goes
+	ctx, cancel = context.WithCancel(context.Background())
+	release = make(chan struct{})
+	go func() {
+		_, err := c.do(ctx, nil, "analyzer", &Task{Data: "8.8.8.8", DataType: "ip"}, run)
+		first <- err
+	}()
+	cancel()
+	<-first
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("run isn't cancelled after its caller has gone")
+	}
+}
+
+var cachedReportJSON = []byte(`
+{
+  "id": "AWOsZ3pPqNgGAnpM4Ui0",
+  "status": "Success",
+  "data": "1.1.1.1",
+  "dataType": "ip",
+  "report": {
+    "success": true,
+    "full": {"country": "AU"},
+    "summary": {"taxonomies": [{"namespace": "MaxMind", "predicate": "Location", "value": "Australia", "level": "info"}]}
+  }
+}`)
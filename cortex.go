@@ -4,12 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 const (
@@ -82,21 +83,34 @@ type Client struct {
 	Opts      *ClientOpts
 	PageSize  int
 
-	Analyzers AnalyzerService
-	Users     UserService
+	Analyzers       AnalyzerService
+	AnalyzerConfigs AnalyzerConfigService
+	Jobs            JobService
+	Organizations   OrganizationService
+	Responders      ResponderService
+	Users           UserService
+
+	capsMu   sync.Mutex
+	caps     *Capabilities
+	capsCall *capsCall
 }
 
 // ClientOpts represent options that are passed to client.
 type ClientOpts struct {
-	Auth       auth
+	Auth       Authenticator
 	HTTPClient *http.Client
-}
 
-// errorMessage is the message that Cortex sends back to user when something
-// went wrong.
-type errorMessage struct {
-	Type    string `json:"type"`
-	Message string `json:"message"`
+	// Retry enables retries of rate-limited and transiently failed
+	// requests, nil disables them
+	Retry *RetryPolicy
+
+	// RateLimit enables client-side rate limiting of analyzer jobs, nil
+	// disables it
+	RateLimit *RateLimit
+
+	// Cache enables reuse of fresh reports for the same observables, nil
+	// disables it
+	Cache *CacheOpts
 }
 
 // NewClient bootstraps a client to interact with Cortex API
@@ -118,12 +132,54 @@ func NewClient(baseurl string, opts *ClientOpts) (*Client, error) {
 		PageSize:  100,
 	}
 
-	c.Analyzers = &AnalyzerServiceOp{client: c}
+	aso := &AnalyzerServiceOp{client: c}
+	if opts.RateLimit != nil {
+		aso.limiter = newRateLimiter(opts.RateLimit)
+	}
+	if opts.Cache != nil {
+		aso.cache = newReportCache(opts.Cache)
+	}
+	c.Analyzers = aso
+	c.AnalyzerConfigs = &AnalyzerConfigServiceOp{client: c}
+	c.Jobs = &JobServiceOp{client: c}
+	c.Organizations = &OrganizationServiceOp{client: c}
+	c.Responders = &ResponderServiceOp{client: c}
 	c.Users = &UserServiceOp{client: c}
 
+	if b, ok := opts.Auth.(clientBinder); ok {
+		b.bind(c)
+	}
+
 	return c, nil
 }
 
+// listPages retrieves all items of a list endpoint page by page using
+// the client's PageSize
+func listPages[T any](ctx context.Context, c *Client, urlStr string) ([]T, *http.Response, error) {
+	var (
+		items []T
+		resp  *http.Response
+	)
+
+	for start := 0; ; start += c.PageSize {
+		req, err := c.NewRequest("GET", fmt.Sprintf("%s?range=%d-%d", urlStr, start, start+c.PageSize), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var page []T
+		resp, err = c.Do(ctx, req, &page)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		items = append(items, page...)
+		if len(page) < c.PageSize {
+			return items, resp, nil
+		}
+	}
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash. If
@@ -162,12 +218,13 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
-	req.Header.Set("Authorization", c.Opts.Auth.Token())
-
 	return req, nil
 }
 
-// NewFileRequest creates an API request with a file http form.
+// NewFileRequest creates an API request with a file http form. The file is
+// streamed from r while the request is being sent, so it is never held in
+// memory entirely. If r is an io.Seeker the request body could be rewound,
+// e.g. to retry the request, and its Content-Length is set.
 func (c *Client) NewFileRequest(method, urlStr string, body interface{}, fileName string, r io.Reader) (*http.Request, error) {
 	if !strings.HasSuffix(c.BaseURL.Path, "/") {
 		return nil, fmt.Errorf("BaseURL must have a trailing slash, but %q does not", c.BaseURL)
@@ -177,49 +234,35 @@ func (c *Client) NewFileRequest(method, urlStr string, body interface{}, fileNam
 		return nil, err
 	}
 
-	var w bytes.Buffer
-	mpw := multipart.NewWriter(&w)
-	fpart, err := mpw.CreateFormFile("attachment", fileName)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = io.Copy(fpart, r)
+	bb, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	jpart, err := mpw.CreateFormField("_json")
+	up, err := newUpload(fileName, r, bb)
 	if err != nil {
 		return nil, err
 	}
 
-	bb, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	_, err = jpart.Write(bb)
+	req, err := http.NewRequest(method, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := mpw.Close(); err != nil {
-		return nil, err
+	req.Body = up.body()
+	if up.seekable {
+		req.GetBody = up.rewind
 	}
-
-	req, err := http.NewRequest(method, u.String(), &w)
-	if err != nil {
-		return nil, err
+	if up.size >= 0 {
+		req.ContentLength = up.size
 	}
 
-	req.Header.Set("Content-Type", mpw.FormDataContentType())
+	req.Header.Set("Content-Type", up.contentType())
 	req.Header.Set("Accept", mediaType)
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
-	req.Header.Set("Authorization", c.Opts.Auth.Token())
-
 	return req, nil
 }
 
@@ -234,7 +277,7 @@ func (c *Client) NewFileRequest(method, urlStr string, body interface{}, fileNam
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
 	req = req.WithContext(ctx)
 
-	resp, err := c.Client.Do(req)
+	resp, err := c.sendWithRetry(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
@@ -270,17 +313,74 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	return resp, err
 }
 
-// checkResponse checks http response status code and returns an error if
-// needed.
+// send authenticates the request and sends it
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.Opts.Auth != nil {
+		if err := c.Opts.Auth.Authenticate(req); err != nil {
+			// the body must be closed as the request is not sent
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	return c.Client.Do(req)
+}
+
+// sendAuthenticated sends the request and authenticates again once if
+// the session is expired and the request could be resent
+func (c *Client) sendAuthenticated(req *http.Request) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	e, ok := c.Opts.Auth.(expirer)
+	if !ok {
+		return resp, err
+	}
+
+	rreq, rerr := rewind(req)
+	if rerr != nil || !e.expire() {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	return c.send(rreq)
+}
+
+// rewind returns a copy of the request that could be sent again
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("request body can't be rewound")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+// checkResponse checks http response status code and returns an
+// *ErrorResponse if needed.
 func checkResponse(r *http.Response) error {
 	if r.StatusCode > 199 && r.StatusCode < 300 {
 		return nil
 	}
 
-	var em errorMessage
+	var em ErrorResponse
 	err := json.NewDecoder(r.Body).Decode(&em)
 	if err != nil {
-		return fmt.Errorf(errUnknownFmt, r.Status)
+		return newErrorResponse(r, "", "")
 	}
-	return fmt.Errorf(errMessageFmt, r.Status, em.Type, em.Message)
+	return newErrorResponse(r, em.Type, em.Message)
 }
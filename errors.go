@@ -0,0 +1,149 @@
+package cortex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound is matched by errors returned for a missing resource
+	ErrNotFound = errors.New("not found")
+
+	// ErrAuthentication is matched by errors returned for an unauthenticated
+	// or unauthorized request
+	ErrAuthentication = errors.New("authentication error")
+
+	// ErrRateLimited is matched by errors returned when Cortex rate limit is
+	// exceeded
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrTimeout is matched by errors returned when a job passed its maximum
+	// execution time
+	ErrTimeout = errors.New("timeout")
+
+	// ErrUnsupported is matched by errors returned when the server doesn't
+	// support the requested feature
+	ErrUnsupported = errors.New("unsupported by server version")
+)
+
+// ErrorResponse is returned when Cortex responds with a non-2xx status code.
+type ErrorResponse struct {
+	Response   *http.Response
+	StatusCode int
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+
+	// err is a sentinel error the response is matched with by errors.Is
+	err error
+}
+
+// Error satisfies an error interface
+func (e *ErrorResponse) Error() string {
+	if e.Type == "" && e.Message == "" {
+		return fmt.Sprintf(errUnknownFmt, e.status())
+	}
+	if e.Type == "" {
+		return fmt.Sprintf("http status: %s, %s", e.status(), e.Message)
+	}
+	return fmt.Sprintf(errMessageFmt, e.status(), e.Type, e.Message)
+}
+
+// Unwrap returns a sentinel error matching the response, if any
+func (e *ErrorResponse) Unwrap() error {
+	return e.err
+}
+
+func (e *ErrorResponse) status() string {
+	if e.Response != nil && e.Response.Status != "" {
+		return e.Response.Status
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// newErrorResponse bootstraps an ErrorResponse and matches it with
+// a sentinel error by status code and Cortex error type.
+func newErrorResponse(r *http.Response, typ, msg string) *ErrorResponse {
+	e := &ErrorResponse{
+		Response:   r,
+		StatusCode: r.StatusCode,
+		Type:       typ,
+		Message:    msg,
+	}
+
+	switch {
+	case r.StatusCode == http.StatusNotFound || typ == "NotFoundError":
+		e.err = ErrNotFound
+	case r.StatusCode == http.StatusUnauthorized || r.StatusCode == http.StatusForbidden ||
+		typ == "AuthenticationError" || typ == "AuthorizationError":
+		e.err = ErrAuthentication
+	case r.StatusCode == http.StatusTooManyRequests || typ == "RateLimitExceeded":
+		e.err = ErrRateLimited
+	case r.StatusCode == http.StatusGatewayTimeout || r.StatusCode == http.StatusRequestTimeout:
+		e.err = ErrTimeout
+	}
+
+	return e
+}
+
+// waitReportError matches an error returned by waitreport with ErrTimeout,
+// because Cortex responds with 500 when the job passed the maximum execution
+// time d.
+func waitReportError(err error, d time.Duration) error {
+	var er *ErrorResponse
+	if errors.As(err, &er) && er.StatusCode == http.StatusInternalServerError {
+		er.err = ErrTimeout
+		if er.Message == "" {
+			er.Message = fmt.Sprintf("job passed maximum execution time %s", d.String())
+		}
+	}
+
+	return err
+}
+
+// unsupportedError matches an error with ErrUnsupported if the endpoint
+// doesn't exist on the server: it responds with 404 without a Cortex error
+// type, which is returned for missing resources, or with 405 or 501.
+func unsupportedError(err error) error {
+	var er *ErrorResponse
+	if !errors.As(err, &er) {
+		return err
+	}
+
+	switch {
+	case er.StatusCode == http.StatusNotFound && er.Type == "",
+		er.StatusCode == http.StatusMethodNotAllowed,
+		er.StatusCode == http.StatusNotImplemented:
+		er.err = ErrUnsupported
+	}
+
+	return err
+}
+
+// IsNotFound reports whether err is caused by a missing resource
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsAuthError reports whether err is caused by failed authentication
+// or insufficient permissions
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuthentication)
+}
+
+// IsRateLimited reports whether err is caused by exceeded rate limit
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsTimeout reports whether err is caused by a job timeout
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout)
+}
+
+// IsUnsupported reports whether err is caused by a feature missing on the
+// server
+func IsUnsupported(err error) bool {
+	return errors.Is(err, ErrUnsupported)
+}
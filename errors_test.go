@@ -0,0 +1,64 @@
+package cortex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorResponseSentinels(t *testing.T) {
+	var tests = []struct {
+		status int
+		body   string
+		is     func(error) bool
+	}{
+		{http.StatusNotFound, `{"type":"NotFoundError","message":"job not found"}`, IsNotFound},
+		{http.StatusForbidden, `{"type":"AuthorizationError","message":"forbidden"}`, IsAuthError},
+		{http.StatusTooManyRequests, ``, IsRateLimited},
+		{http.StatusGatewayTimeout, ``, IsTimeout},
+	}
+
+	for _, tt := range tests {
+		client, mux, _, closer := setup()
+
+		mux.HandleFunc("/"+currentUser, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+
+		_, _, err := client.Users.Current(context.Background())
+		if !tt.is(err) {
+			t.Errorf("status %d: unexpected error %v", tt.status, err)
+		}
+
+		var er *ErrorResponse
+		if !errors.As(err, &er) || er.StatusCode != tt.status || er.Response == nil {
+			t.Errorf("status %d: error %+v is not a valid *ErrorResponse", tt.status, err)
+		}
+
+		closer()
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+	})
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/waitreport", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	aso := client.Analyzers.(*AnalyzerServiceOp)
+	_, err := aso.run(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", &Task{
+		Data:     "1.1.1.1",
+		DataType: "ip",
+	}, time.Second)
+	if !IsTimeout(err) {
+		t.Errorf("run returned %v, want timeout error", err)
+	}
+}
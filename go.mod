@@ -1,3 +1,5 @@
 module github.com/ilyaglow/go-cortex/v3
 
+go 1.18
+
 require github.com/asaskevich/govalidator v0.0.0-20180319081651-7d2e70ef918f
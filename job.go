@@ -10,7 +10,8 @@ import (
 )
 
 const (
-	jobsURL = APIRoute + "/job"
+	jobsURL       = APIRoute + "/job"
+	jobsSearchURL = jobsURL + "/_search"
 )
 
 // Task represents a Cortex task to run
@@ -75,14 +76,6 @@ type Job struct {
 	UpdatedBy            string `json:"updatedBy,omitempty"`
 }
 
-// Taxonomy represents a taxonomy object in a report
-type Taxonomy struct {
-	Predicate string      `json:"predicate"`
-	Namespace string      `json:"namespace"`
-	Value     interface{} `json:"value"`
-	Level     string      `json:"level"`
-}
-
 // Report represents a struct returned by the Cortex
 type Report struct {
 	Job
@@ -94,6 +87,34 @@ func (r *Report) Taxonomies() []Taxonomy {
 	return r.ReportBody.Summary.Taxonomies
 }
 
+// MaxLevel returns the most severe taxonomy level of the report or an empty
+// level if there are no taxonomies
+func (r *Report) MaxLevel() TaxonomyLevel {
+	var max TaxonomyLevel
+	for _, tx := range r.Taxonomies() {
+		if max.Less(tx.Level) {
+			max = tx.Level
+		}
+	}
+
+	return max
+}
+
+// ByNamespace groups taxonomies of the report by namespace
+func (r *Report) ByNamespace() map[string][]Taxonomy {
+	txs := make(map[string][]Taxonomy)
+	for _, tx := range r.Taxonomies() {
+		txs[tx.Namespace] = append(txs[tx.Namespace], tx)
+	}
+
+	return txs
+}
+
+// IsMalicious reports whether any taxonomy of the report is malicious
+func (r *Report) IsMalicious() bool {
+	return r.MaxLevel() == TxMalicious
+}
+
 // Artifact represents an artifact
 type Artifact struct {
 	DataType  string `json:"dataType"`
@@ -129,9 +150,15 @@ type Observable interface {
 // JobService is an interface for managing jobs
 type JobService interface {
 	Get(context.Context, string) (*Job, *http.Response, error)
+	List(context.Context) ([]Job, *http.Response, error)
+	Search(context.Context, *SearchOpts) ([]Job, *http.Response, error)
+	Artifacts(context.Context, string) ([]Artifact, *http.Response, error)
 	GetReport(context.Context, string) (*Report, *http.Response, error)
 	WaitReport(context.Context, string, time.Duration) (*Report, *http.Response, error)
 	Delete(context.Context, string) (*http.Response, error)
+	Abort(context.Context, string) (*http.Response, error)
+	Cancel(context.Context, string, bool) (*http.Response, error)
+	NewTracker(time.Duration, time.Duration) *JobTracker
 }
 
 // JobServiceOp handles cases methods from the Cortex API
@@ -155,6 +182,48 @@ func (j *JobServiceOp) Get(ctx context.Context, jobid string) (*Job, *http.Respo
 	return &job, resp, nil
 }
 
+// List all Cortex jobs with pagination
+func (j *JobServiceOp) List(ctx context.Context) ([]Job, *http.Response, error) {
+	return listPages[Job](ctx, j.client, jobsURL)
+}
+
+// Search finds jobs matching the query. An empty query matches all jobs,
+// Range and Sort are passed to Cortex as is.
+func (j *JobServiceOp) Search(ctx context.Context, opts *SearchOpts) ([]Job, *http.Response, error) {
+	if opts == nil {
+		opts = &SearchOpts{}
+	}
+
+	req, err := j.client.NewRequest("POST", jobsSearchURL, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jobs []Job
+	resp, err := j.client.Do(ctx, req, &jobs)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return jobs, resp, nil
+}
+
+// Artifacts retrieves artifacts extracted by the job
+func (j *JobServiceOp) Artifacts(ctx context.Context, jobid string) ([]Artifact, *http.Response, error) {
+	req, err := j.client.NewRequest("GET", fmt.Sprintf(jobsURL+"/%s/artifacts", jobid), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var artifacts []Artifact
+	resp, err := j.client.Do(ctx, req, &artifacts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return artifacts, resp, nil
+}
+
 // GetReport retrieves the analysis Report by a job ID
 func (j *JobServiceOp) GetReport(ctx context.Context, jobid string) (*Report, *http.Response, error) {
 	req, err := j.client.NewRequest("GET", fmt.Sprintf(jobsURL+"/%s/report", jobid), nil)
@@ -174,22 +243,33 @@ func (j *JobServiceOp) GetReport(ctx context.Context, jobid string) (*Report, *h
 // WaitReport synchronously waits a certain job id for a specified duration of time
 // and returns a report
 func (j *JobServiceOp) WaitReport(ctx context.Context, jid string, d time.Duration) (*Report, *http.Response, error) {
+	var report Report
+	resp, err := j.waitReport(ctx, jid, d, &report)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &report, resp, err
+}
+
+// waitReport waits for a job report and decodes it into v, which allows to
+// reuse it for both analyzer and responder reports
+func (j *JobServiceOp) waitReport(ctx context.Context, jid string, d time.Duration, v interface{}) (*http.Response, error) {
 	sd := strconv.FormatFloat(d.Seconds(), 'f', 2, 64) + "seconds"
 	req, err := j.client.NewRequest("GET", fmt.Sprintf(jobsURL+"/%s/waitreport?atMost=%s", jid, sd), nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var report Report
-	resp, err := j.client.Do(ctx, req, &report)
+	resp, err := j.client.Do(ctx, req, v)
 	if err != nil {
 		if resp != nil {
-			return nil, resp, err
+			return resp, err
 		}
-		return nil, nil, err
+		return nil, err
 	}
 
-	return &report, resp, err
+	return resp, nil
 }
 
 // Delete the job from Cortex. This marks the job as Deleted. However the job's
@@ -207,3 +287,44 @@ func (j *JobServiceOp) Delete(ctx context.Context, jobid string) (*http.Response
 
 	return resp, nil
 }
+
+// Abort stops a running job on the server. It returns an error matching
+// ErrUnsupported if the server doesn't support aborting jobs.
+func (j *JobServiceOp) Abort(ctx context.Context, jobid string) (*http.Response, error) {
+	if err := j.client.supports(ctx, "aborting jobs", func(c *Capabilities) bool {
+		return c.AbortJob
+	}); err != nil {
+		return nil, err
+	}
+
+	req, err := j.client.NewRequest("POST", fmt.Sprintf(jobsURL+"/%s/abort", jobid), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(ctx, req, nil)
+	if err != nil {
+		err = unsupportedError(err)
+		if IsUnsupported(err) {
+			j.client.unsupported(func(c *Capabilities) {
+				c.AbortJob = false
+			})
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// Cancel aborts a job. If the server doesn't support aborting jobs, it
+// returns an error matching ErrUnsupported, unless orDelete is set: then
+// the job is deleted instead. Note that a deleted job isn't stopped and
+// can't be reused by other clients.
+func (j *JobServiceOp) Cancel(ctx context.Context, jobid string, orDelete bool) (*http.Response, error) {
+	resp, err := j.Abort(ctx, jobid)
+	if orDelete && IsUnsupported(err) {
+		return j.Delete(ctx, jobid)
+	}
+
+	return resp, err
+}
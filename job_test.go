@@ -0,0 +1,244 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListJobs(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+jobsURL, func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("range"), "0-100"; got != want {
+			t.Errorf("range = %s, want %s", got, want)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(jobsJSON)
+	})
+
+	got, _, err := client.Jobs.List(context.Background())
+	if err != nil {
+		t.Errorf("Jobs.List returned error: %v", err)
+	}
+	if want := wantJobs; !reflect.DeepEqual(got, want) {
+		t.Errorf("Jobs.List = %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchJobs(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+jobsSearchURL, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"query": map[string]interface{}{
+				"_field": "status",
+				"_value": "Success",
+			},
+			"range": "0-10",
+			"sort":  []interface{}{"-createdAt"},
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("search body = %+v, want %+v", body, want)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(jobsJSON)
+	})
+
+	got, _, err := client.Jobs.Search(context.Background(), &SearchOpts{
+		Query: Eq("status", "Success"),
+		Range: "0-10",
+		Sort:  []string{"-createdAt"},
+	})
+	if err != nil {
+		t.Errorf("Jobs.Search returned error: %v", err)
+	}
+	if want := wantJobs; !reflect.DeepEqual(got, want) {
+		t.Errorf("Jobs.Search = %+v, want %+v", got, want)
+	}
+}
+
+func TestJobArtifacts(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(artifactsJSON)
+	})
+
+	got, _, err := client.Jobs.Artifacts(context.Background(), "AWOsZ3pPqNgGAnpM4Ui0")
+	if err != nil {
+		t.Errorf("Jobs.Artifacts returned error: %v", err)
+	}
+	if want := wantArtifacts; !reflect.DeepEqual(got, want) {
+		t.Errorf("Jobs.Artifacts = %+v, want %+v", got, want)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	var tests = []struct {
+		abort    func(w http.ResponseWriter)
+		orDelete bool
+		deleted  bool
+		err      error
+	}{
+		{func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) }, true, false, nil},
+		{func(w http.ResponseWriter) { http.NotFound(w, nil) }, false, false, ErrUnsupported},
+		{func(w http.ResponseWriter) { http.NotFound(w, nil) }, true, true, nil},
+		{func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"NotFoundError","message":"job not found"}`))
+		}, true, false, ErrNotFound},
+	}
+
+	for i, tt := range tests {
+		client, mux, _, closer := setup()
+
+		var deleted bool
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/abort", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" {
+				t.Errorf("method = %s, want POST", r.Method)
+			}
+			tt.abort(w)
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0", func(w http.ResponseWriter, r *http.Request) {
+			deleted = r.Method == "DELETE"
+		})
+
+		_, err := client.Jobs.Cancel(context.Background(), "AWOsZ3pPqNgGAnpM4Ui0", tt.orDelete)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%d: Jobs.Cancel returned %v, want %v", i, err, tt.err)
+		}
+		if deleted != tt.deleted {
+			t.Errorf("%d: job deleted = %t, want %t", i, deleted, tt.deleted)
+		}
+
+		closer()
+	}
+}
+
+func TestRunAbortsJob(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		client, mux, _, closer := setup()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		aborted := make(chan struct{})
+		mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/waitreport", func(w http.ResponseWriter, r *http.Request) {
+			cancel()
+			<-r.Context().Done()
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/abort", func(w http.ResponseWriter, r *http.Request) {
+			close(aborted)
+			w.WriteHeader(status)
+		})
+		mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("%d: the job is deleted", status)
+		})
+
+		aso := client.Analyzers.(*AnalyzerServiceOp)
+		_, err := aso.run(ctx, "c0b3f12a64d3fa2010ef2df4950d17b4", &Task{Data: "1.1.1.1", DataType: "ip"}, time.Minute)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%d: run returned %v, want %v", status, err, context.Canceled)
+		}
+		if left := err != nil && strings.Contains(err.Error(), "left running"); left != (status != http.StatusOK) {
+			t.Errorf("%d: run returned %v", status, err)
+		}
+
+		select {
+		case <-aborted:
+		default:
+			t.Errorf("%d: the job is not aborted", status)
+		}
+
+		closer()
+	}
+}
+
+var jobsJSON = []byte(`
+[
+  {
+    "id": "AWOsZ3pPqNgGAnpM4Ui0",
+    "analyzerDefinitionId": "MaxMind_GeoIP_3_0",
+    "analyzerId": "c0b3f12a64d3fa2010ef2df4950d17b4",
+    "analyzerName": "MaxMind_GeoIP_3_0",
+    "status": "Success",
+    "organization": "test",
+    "data": "1.1.1.1",
+    "dataType": "ip",
+    "tlp": 2,
+    "message": "",
+    "startDate": 1527178197172,
+    "endDate": 1527178198172,
+    "date": 1527178197000,
+    "createdAt": 1527178197000,
+    "createdBy": "test1"
+  }
+]
+`)
+
+var wantJobs = []Job{
+	{
+		Task: Task{
+			Data:     "1.1.1.1",
+			DataType: "ip",
+			TLP:      &TLPAmber,
+		},
+		ID:                   "AWOsZ3pPqNgGAnpM4Ui0",
+		AnalyzerDefinitionID: "MaxMind_GeoIP_3_0",
+		AnalyzerID:           "c0b3f12a64d3fa2010ef2df4950d17b4",
+		AnalyzerName:         "MaxMind_GeoIP_3_0",
+		Status:               "Success",
+		Organization:         "test",
+		StartDate:            1527178197172,
+		EndDate:              1527178198172,
+		Date:                 1527178197000,
+		CreatedAt:            1527178197000,
+		CreatedBy:            "test1",
+	},
+}
+
+var artifactsJSON = []byte(`
+[
+  {
+    "dataType": "domain",
+    "createdBy": "test1",
+    "createdAt": 1527178198172,
+    "data": "one.one.one.one",
+    "tlp": 2,
+    "pap": 2,
+    "id": "AWOsZ4AbqNgGAnpM4Ui1"
+  }
+]
+`)
+
+var wantArtifacts = []Artifact{
+	{
+		DataType:  "domain",
+		CreatedBy: "test1",
+		CreatedAt: 1527178198172,
+		Data:      "one.one.one.one",
+		TLP:       TLPAmber,
+		PAP:       PAPAmber,
+		ID:        "AWOsZ4AbqNgGAnpM4Ui1",
+	},
+}
@@ -0,0 +1,98 @@
+package cortex
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// JobDirEnv is an environment variable with a Cortex 3 job directory
+const JobDirEnv = "CORTEX_JOB_DIRECTORY"
+
+// defaultJobDir is where Cortex 3 mounts the job directory into analyzer
+// containers
+var defaultJobDir = "/job"
+
+// DetectJobDir returns a Cortex 3 job directory, which is taken from the first
+// command line argument, JobDirEnv or /job. It returns an empty string if none
+// of them has input/input.json, so the job is passed through stdin and stdout
+// like in Cortex 2.
+func DetectJobDir() string {
+	var dirs []string
+	if len(os.Args) > 1 {
+		dirs = append(dirs, os.Args[1])
+	}
+	dirs = append(dirs, os.Getenv(JobDirEnv), defaultJobDir)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if fi, err := os.Stat(jobInputPath(dir)); err == nil && fi.Mode().IsRegular() {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+func jobInputPath(dir string) string {
+	return filepath.Join(dir, "input", "input.json")
+}
+
+func jobOutputPath(dir string) string {
+	return filepath.Join(dir, "output", "output.json")
+}
+
+// RunAnalyzerInDir runs fn like RunAnalyzerWith, but reads the job input from
+// input/input.json of the job directory and writes the report to
+// output/output.json
+func RunAnalyzerInDir(ctx context.Context, dir string, fn AnalyzerFunc) error {
+	f, err := os.Open(jobInputPath(dir))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out, err := createJobOutput(dir)
+	if err != nil {
+		return err
+	}
+
+	err = runAnalyzer(ctx, f, out, dir, fn)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// createJobOutput creates output/output.json in the job directory
+func createJobOutput(dir string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "output"), 0755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(jobOutputPath(dir))
+}
+
+// newDirInput reads the job input from the job directory
+func newDirInput(dir string) (*JobInput, *http.Client, error) {
+	f, err := os.Open(jobInputPath(dir))
+	if err != nil {
+		return nil, http.DefaultClient, err
+	}
+	defer f.Close()
+
+	return newInput(f, dir)
+}
+
+// setDir binds the input to the job directory and resolves the file
+// relative to its input directory
+func (j *JobInput) setDir(dir string) {
+	j.dir = dir
+	if dir != "" && j.File != "" && !filepath.IsAbs(j.File) {
+		j.File = filepath.Join(dir, "input", j.File)
+	}
+}
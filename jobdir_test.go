@@ -0,0 +1,120 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAnalyzerInDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "go-cortex-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "input"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input := []byte(`{"dataType":"file","file":"attachment","filename":"sample.txt"}`)
+	if err := os.WriteFile(jobInputPath(dir), input, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "input", "attachment"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv(JobDirEnv, dir)
+	defer os.Unsetenv(JobDirEnv)
+	if got := DetectJobDir(); got != dir {
+		t.Errorf("DetectJobDir() = %q, want %q", got, dir)
+	}
+
+	err = RunAnalyzerInDir(context.Background(), dir, func(ctx context.Context, in *JobInput) (*AnalyzerReport, error) {
+		b, err := os.ReadFile(in.File)
+		if err != nil {
+			return nil, err
+		}
+		return in.NewReport(map[string]string{"content": string(b)}, nil)
+	})
+	if err != nil {
+		t.Fatalf("RunAnalyzerInDir returned error: %v", err)
+	}
+
+	b, err := os.ReadFile(jobOutputPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report AnalyzerReport
+	if err := json.Unmarshal(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	if full, ok := report.FullReport.(map[string]interface{}); !report.Success || !ok || full["content"] != "hello" {
+		t.Errorf("got output %s", b)
+	}
+}
+
+func TestDetectJobDirStdin(t *testing.T) {
+	defer func(d string) { defaultJobDir = d }(defaultJobDir)
+	defaultJobDir = filepath.Join(os.TempDir(), "go-cortex-no-such-job")
+
+	os.Setenv(JobDirEnv, "")
+	defer os.Unsetenv(JobDirEnv)
+	if got := DetectJobDir(); got != "" {
+		t.Errorf("DetectJobDir() = %q, want no job directory", got)
+	}
+}
+
+func TestAddArtifacts(t *testing.T) {
+	dir, err := os.MkdirTemp("", "go-cortex-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := &JobInput{Config: cfg{"auto_extract_artifacts": true}}
+	if err := in.AddFileArtifact("invoice.pdf", strings.NewReader("%PDF"), ExtractedArtifact{}); err == nil {
+		t.Error("file artifact is added without a job directory")
+	}
+	in.setDir(dir)
+
+	in.AddArtifact(ExtractedArtifact{Type: "ip", Value: "8.8.8.8", Message: "resolved", Tags: []string{"dns"}, TLP: &TLPGreen})
+	in.AddArtifact(ExtractedArtifact{Type: "domain", Value: "example.com"})
+	in.AddArtifact(ExtractedArtifact{Type: "domain", Value: "example.com", Message: "duplicate"})
+	err = in.AddFileArtifact("invoice.pdf", strings.NewReader("%PDF"), ExtractedArtifact{ContentType: "application/pdf"})
+	if err != nil {
+		t.Fatalf("AddFileArtifact returned error: %v", err)
+	}
+
+	r, err := in.NewReport(map[string]string{"ip": "8.8.8.8", "domain": "example.com", "md5": "d41d8cd98f00b204e9800998ecf8427e"}, nil)
+	if err != nil {
+		t.Fatalf("NewReport returned error: %v", err)
+	}
+
+	if len(r.Artifacts) != 4 {
+		t.Fatalf("got artifacts %+v, want 4", r.Artifacts)
+	}
+	// added artifacts replace auto-extracted ones with the same value
+	if a := r.Artifacts[0]; a.Type != "ip" || a.Message != "resolved" {
+		t.Errorf("added artifact is replaced with %+v", a)
+	}
+	if a := r.Artifacts[1]; a.Type != "domain" || a.Value != "example.com" || a.Message != "" {
+		t.Errorf("unexpected added artifact %+v", a)
+	}
+	if a := r.Artifacts[2]; a.Type != "file" || a.FileName != "invoice.pdf" || a.ContentType != "application/pdf" {
+		t.Errorf("unexpected file artifact %+v", a)
+	} else if b, err := os.ReadFile(filepath.Join(dir, "output", a.File)); err != nil || string(b) != "%PDF" {
+		t.Errorf("file artifact content = %q, %v", b, err)
+	}
+	if a := r.Artifacts[3]; a.Type != "hash" || a.Value != "d41d8cd98f00b204e9800998ecf8427e" {
+		t.Errorf("unexpected extracted artifact %+v", a)
+	}
+
+	b, _ := json.Marshal(r.Artifacts[0])
+	if want := `{"dataType":"ip","data":"8.8.8.8","message":"resolved","tags":["dns"],"tlp":1}`; string(b) != want {
+		t.Errorf("artifact JSON = %s, want %s", b, want)
+	}
+}
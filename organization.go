@@ -0,0 +1,137 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	organizationsURL = APIRoute + "/organization"
+)
+
+// Organization represents a Cortex Organization
+type Organization struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"createdAt"`
+	CreatedBy   string `json:"createdBy"`
+	UpdatedAt   int64  `json:"updatedAt,omitempty"`
+	UpdatedBy   string `json:"updatedBy,omitempty"`
+}
+
+// OrganizationService is an interface for managing organizations
+type OrganizationService interface {
+	Create(context.Context, *Organization) (*Organization, *http.Response, error)
+	Get(context.Context, string) (*Organization, *http.Response, error)
+	Update(context.Context, string, *Organization) (*Organization, *http.Response, error)
+	Disable(context.Context, string) (*http.Response, error)
+	List(context.Context) ([]Organization, *http.Response, error)
+	Users(context.Context, string) ([]User, *http.Response, error)
+	Analyzers(context.Context, string) ([]Analyzer, *http.Response, error)
+}
+
+// OrganizationServiceOp handles organization methods from Cortex API
+type OrganizationServiceOp struct {
+	client *Client
+}
+
+// Create a new organization. Only Name, Description and Status are used,
+// Status defaults to "Active".
+func (o *OrganizationServiceOp) Create(ctx context.Context, org *Organization) (*Organization, *http.Response, error) {
+	status := org.Status
+	if status == "" {
+		status = "Active"
+	}
+
+	req, err := o.client.NewRequest("POST", organizationsURL, map[string]string{
+		"name":        org.Name,
+		"description": org.Description,
+		"status":      status,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created Organization
+	resp, err := o.client.Do(ctx, req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Get retrieves an organization by its ID
+func (o *OrganizationServiceOp) Get(ctx context.Context, id string) (*Organization, *http.Response, error) {
+	req, err := o.client.NewRequest("GET", fmt.Sprintf(organizationsURL+"/%s", id), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var org Organization
+	resp, err := o.client.Do(ctx, req, &org)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &org, resp, nil
+}
+
+// Update an organization's description and status. Empty fields are left
+// untouched, organization's name can't be changed.
+func (o *OrganizationServiceOp) Update(ctx context.Context, id string, org *Organization) (*Organization, *http.Response, error) {
+	fields := make(map[string]string)
+	if org.Description != "" {
+		fields["description"] = org.Description
+	}
+	if org.Status != "" {
+		fields["status"] = org.Status
+	}
+
+	req, err := o.client.NewRequest("PATCH", fmt.Sprintf(organizationsURL+"/%s", id), fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updated Organization
+	resp, err := o.client.Do(ctx, req, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Disable the organization. Cortex doesn't remove organizations, it locks
+// them instead.
+func (o *OrganizationServiceOp) Disable(ctx context.Context, id string) (*http.Response, error) {
+	req, err := o.client.NewRequest("DELETE", fmt.Sprintf(organizationsURL+"/%s", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// List all Cortex organizations with pagination
+func (o *OrganizationServiceOp) List(ctx context.Context) ([]Organization, *http.Response, error) {
+	return listPages[Organization](ctx, o.client, organizationsURL)
+}
+
+// Users lists all users of the organization with pagination
+func (o *OrganizationServiceOp) Users(ctx context.Context, id string) ([]User, *http.Response, error) {
+	return listPages[User](ctx, o.client, fmt.Sprintf(organizationsURL+"/%s/user", id))
+}
+
+// Analyzers lists all analyzers enabled for the organization with pagination
+func (o *OrganizationServiceOp) Analyzers(ctx context.Context, id string) ([]Analyzer, *http.Response, error) {
+	return listPages[Analyzer](ctx, o.client, fmt.Sprintf(organizationsURL+"/%s/analyzer", id))
+}
@@ -0,0 +1,97 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreateOrganization(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+organizationsURL, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"name":        "acme",
+			"description": "ACME Corp",
+			"status":      "Active",
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("create body = %+v, want %+v", body, want)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write(organizationJSON)
+	})
+
+	got, _, err := client.Organizations.Create(context.Background(), &Organization{
+		Name:        "acme",
+		Description: "ACME Corp",
+	})
+	if err != nil {
+		t.Errorf("Organizations.Create returned error: %v", err)
+	}
+	if want := wantOrganization; !reflect.DeepEqual(got, want) {
+		t.Errorf("Organizations.Create = %+v, want %+v", got, want)
+	}
+}
+
+func TestListOrganizationsPaged(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+	client.PageSize = 2
+
+	var ranges []string
+	mux.HandleFunc("/"+organizationsURL, func(w http.ResponseWriter, r *http.Request) {
+		rng := r.URL.Query().Get("range")
+		ranges = append(ranges, rng)
+
+		switch rng {
+		case "0-2":
+			fmt.Fprintf(w, "[%s,%s]", organizationJSON, organizationJSON)
+		default:
+			fmt.Fprintf(w, "[%s]", organizationJSON)
+		}
+	})
+
+	got, _, err := client.Organizations.List(context.Background())
+	if err != nil {
+		t.Errorf("Organizations.List returned error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("Organizations.List returned %d organizations, want 3", len(got))
+	}
+	if want := []string{"0-2", "2-4"}; !reflect.DeepEqual(ranges, want) {
+		t.Errorf("requested ranges = %v, want %v", ranges, want)
+	}
+}
+
+var organizationJSON = []byte(`
+{
+  "id": "acme",
+  "name": "acme",
+  "description": "ACME Corp",
+  "status": "Active",
+  "createdAt": 1527178197172,
+  "createdBy": "admin"
+}`)
+
+var wantOrganization = &Organization{
+	ID:          "acme",
+	Name:        "acme",
+	Description: "ACME Corp",
+	Status:      "Active",
+	CreatedAt:   1527178197172,
+	CreatedBy:   "admin",
+}
@@ -0,0 +1,79 @@
+package cortex
+
+// Query represents a Cortex search query. Use the helper functions below to
+// build one, e.g.:
+//
+//	cortex.And(
+//		cortex.Eq("status", "Success"),
+//		cortex.Gt("createdAt", 1527178197172),
+//	)
+type Query map[string]interface{}
+
+// SearchOpts represents a body of a search request
+type SearchOpts struct {
+	Query Query    `json:"query,omitempty"`
+	Range string   `json:"range,omitempty"` // e.g. "0-100" or "all"
+	Sort  []string `json:"sort,omitempty"`  // e.g. "-createdAt"
+}
+
+// Eq matches documents where field is equal to value
+func Eq(field string, value interface{}) Query {
+	return Query{"_field": field, "_value": value}
+}
+
+// In matches documents where field is equal to one of the values
+func In(field string, values ...interface{}) Query {
+	return Query{"_in": map[string]interface{}{
+		"_field":  field,
+		"_values": values,
+	}}
+}
+
+// Lt matches documents where field is less than value
+func Lt(field string, value interface{}) Query {
+	return Query{"_lt": map[string]interface{}{field: value}}
+}
+
+// Lte matches documents where field is less than or equal to value
+func Lte(field string, value interface{}) Query {
+	return Query{"_lte": map[string]interface{}{field: value}}
+}
+
+// Gt matches documents where field is greater than value
+func Gt(field string, value interface{}) Query {
+	return Query{"_gt": map[string]interface{}{field: value}}
+}
+
+// Gte matches documents where field is greater than or equal to value
+func Gte(field string, value interface{}) Query {
+	return Query{"_gte": map[string]interface{}{field: value}}
+}
+
+// Between matches documents where field is in the [from, to) range
+func Between(field string, from, to interface{}) Query {
+	return Query{"_between": map[string]interface{}{
+		"_field": field,
+		"_from":  from,
+		"_to":    to,
+	}}
+}
+
+// QueryString matches documents using a Lucene-like query string
+func QueryString(q string) Query {
+	return Query{"_string": q}
+}
+
+// And matches documents satisfying all of the queries
+func And(qs ...Query) Query {
+	return Query{"_and": qs}
+}
+
+// Or matches documents satisfying any of the queries
+func Or(qs ...Query) Query {
+	return Query{"_or": qs}
+}
+
+// Not matches documents that don't satisfy the query
+func Not(q Query) Query {
+	return Query{"_not": q}
+}
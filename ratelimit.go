@@ -0,0 +1,173 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateUnits maps Cortex analyzer rate units to durations
+var rateUnits = map[string]time.Duration{
+	"Second": time.Second,
+	"Minute": time.Minute,
+	"Hour":   time.Hour,
+	"Day":    24 * time.Hour,
+	"Month":  30 * 24 * time.Hour,
+}
+
+// RateLimit configures client-side rate limiting of jobs started by
+// AnalyzerService. Each analyzer is limited according to its Rate and
+// RateUnit unless it is overridden. StartJob blocks until the analyzer's
+// budget allows to start a job or the context is done.
+type RateLimit struct {
+	// Overrides maps an analyzer name or ID to a rate that is used instead
+	// of the analyzer's own one. Zero Rate disables limiting.
+	Overrides map[string]Rate
+}
+
+// Rate represents an amount of jobs allowed per a period of time
+type Rate struct {
+	Limit int
+	Per   time.Duration
+}
+
+// Limit returns analyzer's rate limit, it's false if the analyzer is not
+// limited
+func (a *Analyzer) Limit() (Rate, bool) {
+	per, ok := rateUnits[a.RateUnit]
+	if !ok || a.Rate <= 0 {
+		return Rate{}, false
+	}
+
+	return Rate{Limit: a.Rate, Per: per}, true
+}
+
+// rateLimiter holds a token bucket per analyzer ID
+type rateLimiter struct {
+	opts *RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newRateLimiter(opts *RateLimit) *rateLimiter {
+	return &rateLimiter{
+		opts:    opts,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// wait blocks until a job could be started by the analyzer with the id
+func (l *rateLimiter) wait(ctx context.Context, a *AnalyzerServiceOp, id string) error {
+	b, err := l.bucket(ctx, a, id)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return nil
+	}
+
+	d := b.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// bucket returns a bucket for the analyzer, it fetches the analyzer to get
+// its rate on the first call. Nil bucket means the analyzer is not limited.
+func (l *rateLimiter) bucket(ctx context.Context, a *AnalyzerServiceOp, id string) (*bucket, error) {
+	l.mu.Lock()
+	b, ok := l.buckets[id]
+	l.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	r, limited := l.opts.Overrides[id]
+	if !limited {
+		an, _, err := a.getByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("can't get analyzer %s rate: %w", id, err)
+		}
+
+		if r, limited = l.opts.Overrides[an.Name]; !limited {
+			r, limited = an.Limit()
+		}
+	}
+
+	if limited && r.Limit > 0 && r.Per > 0 {
+		b = newBucket(r)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if existing, ok := l.buckets[id]; ok {
+		return existing, nil
+	}
+	l.buckets[id] = b
+
+	return b, nil
+}
+
+// bucket is a token bucket, which starts full
+type bucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	perToken time.Duration
+	last     time.Time
+}
+
+func newBucket(r Rate) *bucket {
+	return &bucket{
+		capacity: float64(r.Limit),
+		tokens:   float64(r.Limit),
+		perToken: r.Per / time.Duration(r.Limit),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token and returns a duration to wait before it becomes
+// available. Tokens could go below zero, which queues the callers.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(b.perToken)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens * float64(b.perToken))
+}
+
+// cancel returns a reserved token
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,70 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Now()
+	b := newBucket(Rate{Limit: 2, Per: time.Minute})
+	b.last = now
+
+	var tests = []struct {
+		at   time.Duration
+		want time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 30 * time.Second},
+		{0, time.Minute},
+		{90 * time.Second, 0},
+	}
+
+	for i, tt := range tests {
+		if got := b.reserve(now.Add(tt.at)); got != tt.want {
+			t.Errorf("reserve #%d = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitedStartJob(t *testing.T) {
+	client, mux, serverURL, closer := setup()
+	defer closer()
+
+	client, _ = NewClient(serverURL+"/", &ClientOpts{
+		Auth: &APIAuth{APIKey: "dummy-key"},
+		RateLimit: &RateLimit{
+			Overrides: map[string]Rate{
+				"MaxMind_GeoIP_3_0": {Limit: 1, Per: time.Hour},
+			},
+		},
+	})
+
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wantList[0])
+	})
+	var started int
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+		started++
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+	})
+
+	task := &Task{Data: "1.1.1.1", DataType: "ip"}
+	if _, _, err := client.Analyzers.StartJob(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", task); err != nil {
+		t.Fatalf("first StartJob returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Analyzers.StartJob(ctx, "c0b3f12a64d3fa2010ef2df4950d17b4", task); err != context.DeadlineExceeded {
+		t.Fatalf("second StartJob returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if started != 1 {
+		t.Errorf("started %d jobs, want 1", started)
+	}
+}
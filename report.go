@@ -0,0 +1,110 @@
+package cortex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// reportTypes maps analyzer definition IDs to types of their full reports
+var reportTypes = struct {
+	sync.RWMutex
+	m map[string]reflect.Type
+}{m: make(map[string]reflect.Type)}
+
+// RegisterReportType registers a type of the full report for the analyzer
+// definition, e.g. "VirusTotal_GetReport_3_0". Once registered,
+// ReportBody.FullReport of the analyzer's reports is decoded as a pointer to
+// the prototype's type when they are fetched.
+func RegisterReportType(definitionID string, prototype interface{}) {
+	t := reflect.TypeOf(prototype)
+	if t == nil {
+		panic("cortex: RegisterReportType of nil prototype")
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	reportTypes.Lock()
+	defer reportTypes.Unlock()
+	reportTypes.m[definitionID] = t
+}
+
+func reportType(definitionID string) (reflect.Type, bool) {
+	reportTypes.RLock()
+	defer reportTypes.RUnlock()
+	t, ok := reportTypes.m[definitionID]
+	return t, ok
+}
+
+// UnmarshalJSON decodes the report and its full report into a type
+// registered for the analyzer definition, if any
+func (r *Report) UnmarshalJSON(data []byte) error {
+	type report Report
+	if err := json.Unmarshal(data, (*report)(r)); err != nil {
+		return err
+	}
+
+	t, ok := reportType(r.AnalyzerDefinitionID)
+	if !ok {
+		return nil
+	}
+
+	var raw struct {
+		Body struct {
+			Full json.RawMessage `json:"full"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.Body.Full) == 0 || bytes.Equal(raw.Body.Full, []byte("null")) {
+		return nil
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(raw.Body.Full, v.Interface()); err != nil {
+		return fmt.Errorf("can't decode full report of %s: %w", r.AnalyzerDefinitionID, err)
+	}
+	r.ReportBody.FullReport = v.Interface()
+
+	return nil
+}
+
+// DecodeFull decodes the full report into v, which should be a pointer
+func (r *Report) DecodeFull(v interface{}) error {
+	full := r.ReportBody.FullReport
+
+	// the full report is already decoded into the registered type
+	if fv := reflect.ValueOf(full); fv.Kind() == reflect.Ptr && !fv.IsNil() && fv.Type() == reflect.TypeOf(v) {
+		reflect.ValueOf(v).Elem().Set(fv.Elem())
+		return nil
+	}
+
+	b, err := json.Marshal(full)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// RunTyped runs the analyzer like AnalyzerService.Run and decodes its full
+// report into T
+func RunTyped[T any](ctx context.Context, a AnalyzerService, anid string, o Observable, d time.Duration) (*T, *Report, error) {
+	r, err := a.Run(ctx, anid, o, d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v T
+	if err := r.DecodeFull(&v); err != nil {
+		return nil, r, err
+	}
+
+	return &v, r, nil
+}
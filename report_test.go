@@ -0,0 +1,78 @@
+package cortex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type maxMindReport struct {
+	Country string `json:"country"`
+}
+
+func TestReportDecodeFull(t *testing.T) {
+	r := &Report{ReportBody: ReportBody{FullReport: map[string]interface{}{"country": "AU"}}}
+
+	var full maxMindReport
+	if err := r.DecodeFull(&full); err != nil {
+		t.Fatalf("Report.DecodeFull returned error: %v", err)
+	}
+	if full.Country != "AU" {
+		t.Errorf("country = %s, want AU", full.Country)
+	}
+}
+
+func TestRegisterReportType(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	RegisterReportType("Test_MaxMind_GeoIP_3_0", maxMindReport{})
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/report", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0","analyzerDefinitionId":"Test_MaxMind_GeoIP_3_0","report":{"success":true,"full":{"country":"AU"}}}`))
+	})
+
+	r, _, err := client.Jobs.GetReport(context.Background(), "AWOsZ3pPqNgGAnpM4Ui0")
+	if err != nil {
+		t.Fatalf("Jobs.GetReport returned error: %v", err)
+	}
+
+	full, ok := r.ReportBody.FullReport.(*maxMindReport)
+	if !ok || full.Country != "AU" {
+		t.Fatalf("FullReport = %#v, want *maxMindReport", r.ReportBody.FullReport)
+	}
+
+	var decoded maxMindReport
+	if err := r.DecodeFull(&decoded); err != nil || decoded != *full {
+		t.Errorf("Report.DecodeFull = %+v, %v, want %+v", decoded, err, *full)
+	}
+}
+
+func TestRunTyped(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+analyzersURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(analyzersJSON)
+	})
+	mux.HandleFunc("/"+analyzersURL+"/"+wantList[0].ID, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"` + wantList[0].ID + `"}`))
+	})
+	mux.HandleFunc("/"+analyzersURL+"/"+wantList[0].ID+"/run", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+	})
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/waitreport", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(cachedReportJSON)
+	})
+
+	full, r, err := RunTyped[maxMindReport](context.Background(), client.Analyzers, wantList[0].Name, &Task{
+		Data:     "1.1.1.1",
+		DataType: "ip",
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("RunTyped returned error: %v", err)
+	}
+	if full.Country != "AU" || r.ID != "AWOsZ3pPqNgGAnpM4Ui0" {
+		t.Errorf("RunTyped = %+v, %+v", full, r)
+	}
+}
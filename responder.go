@@ -0,0 +1,215 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	respondersURL    = APIRoute + "/responder"
+	respondersByType = respondersURL + "/type/"
+)
+
+// Responder defines a specific Cortex Responder
+type Responder struct {
+	Author        string                 `json:"author"`
+	BaseConfig    string                 `json:"baseConfig"`
+	Configuration map[string]interface{} `json:"configuration"`
+	CreatedAt     int64                  `json:"createdAt"`
+	CreatedBy     string                 `json:"createdBy"`
+	DataTypeList  []string               `json:"dataTypeList"`
+	DefinitionID  string                 `json:"responderDefinitionId"`
+	Description   string                 `json:"description"`
+	ID            string                 `json:"id"`
+	License       string                 `json:"license"`
+	Name          string                 `json:"name"`
+	Rate          int                    `json:"rate,omitempty"`
+	RateUnit      string                 `json:"rateUnit,omitempty"`
+	URL           string                 `json:"url"`
+	UpdatedAt     int64                  `json:"updatedAt,omitempty"`
+	UpdatedBy     string                 `json:"updatedBy,omitempty"`
+	Version       string                 `json:"version"`
+}
+
+// Action represents a responder task to run. Data is usually a TheHive
+// object (case, alert, case task, case artifact) and DataType is its type,
+// e.g. "thehive:case".
+type Action struct {
+	Data       interface{} `json:"data"`
+	DataType   string      `json:"dataType"`
+	Label      string      `json:"label,omitempty"`
+	TLP        *TLP        `json:"tlp,omitempty"`
+	PAP        *PAP        `json:"pap,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+// Operation represents an operation that a responder asks TheHive to
+// perform, e.g. AddTagToCase or MarkAlertAsRead. Only fields relevant to the
+// operation's Type are set.
+type Operation struct {
+	Type        string      `json:"type"`
+	Tag         string      `json:"tag,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Content     string      `json:"content,omitempty"`
+	Owner       string      `json:"owner,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Tpe         string      `json:"tpe,omitempty"`
+	Data        string      `json:"data,omitempty"`
+	DataType    string      `json:"dataType,omitempty"`
+	Message     string      `json:"message,omitempty"`
+}
+
+// ActionReport represents a responder job report returned by the Cortex
+type ActionReport struct {
+	Job
+	ReportBody ActionReportBody `json:"report,omitempty"`
+}
+
+// Operations is a shortcut to get operations from the report
+func (r *ActionReport) Operations() []Operation {
+	return r.ReportBody.Operations
+}
+
+// ActionReportBody represents a report with responder results
+type ActionReportBody struct {
+	FullReport   interface{} `json:"full,omitempty"`
+	Success      bool        `json:"success,omitempty"`
+	Operations   []Operation `json:"operations,omitempty"`
+	ErrorMessage string      `json:"errorMessage,omitempty"`
+	Input        string      `json:"input,omitempty"`
+}
+
+// ResponderService is an interface for managing responders. Responders are
+// run directly through Cortex with StartAction. TheHive's
+// /api/connector/cortex/action endpoint is not covered: it's served by
+// TheHive rather than Cortex, so it can't be reached with this client.
+type ResponderService interface {
+	Get(context.Context, string) (*Responder, *http.Response, error)
+	List(context.Context) ([]Responder, *http.Response, error)
+	ListByType(context.Context, string) ([]Responder, *http.Response, error)
+	Run(context.Context, string, *Action, time.Duration) (*ActionReport, error)
+	StartAction(context.Context, string, *Action) (*Job, *http.Response, error)
+}
+
+// ResponderServiceOp handles responder methods from Cortex API
+type ResponderServiceOp struct {
+	client *Client
+}
+
+// Get a specified Cortex responder by its name
+func (r *ResponderServiceOp) Get(ctx context.Context, id string) (*Responder, *http.Response, error) {
+	rs, resp, err := r.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rid string
+	for i := range rs {
+		if rs[i].Name == id {
+			rid = rs[i].ID
+			break
+		}
+	}
+
+	if rid == "" {
+		return nil, resp, fmt.Errorf("no responder found with name %s", id)
+	}
+
+	req, err := r.client.NewRequest("GET", fmt.Sprintf(respondersURL+"/%s", rid), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res Responder
+	resp, err = r.client.Do(ctx, req, &res)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &res, resp, err
+}
+
+// List all Cortex responders with pagination
+func (r *ResponderServiceOp) List(ctx context.Context) ([]Responder, *http.Response, error) {
+	if err := r.supported(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	rs, resp, err := listPages[Responder](ctx, r.client, respondersURL)
+	return rs, resp, unsupportedError(err)
+}
+
+// ListByType lists Cortex responders by datatype, e.g. "thehive:case"
+func (r *ResponderServiceOp) ListByType(ctx context.Context, t string) ([]Responder, *http.Response, error) {
+	if err := r.supported(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := r.client.NewRequest("GET", respondersByType+t, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responders []Responder
+	resp, err := r.client.Do(ctx, req, &responders)
+	if err != nil {
+		return nil, resp, unsupportedError(err)
+	}
+
+	return responders, resp, nil
+}
+
+// Run will start the action using specified responder,
+// wait for a certain duration and return a report
+func (r *ResponderServiceOp) Run(ctx context.Context, rid string, a *Action, d time.Duration) (*ActionReport, error) {
+	res, _, err := r.Get(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	j, _, err := r.StartAction(ctx, res.ID, a)
+	if err != nil {
+		return nil, err
+	}
+
+	jso := &JobServiceOp{r.client}
+	var report ActionReport
+	_, err = jso.waitReport(ctx, j.ID, d, &report)
+	if err != nil {
+		return nil, waitReportError(err, d)
+	}
+
+	return &report, nil
+}
+
+// StartAction starts a responder job using Cortex Responder ID
+func (r *ResponderServiceOp) StartAction(ctx context.Context, rid string, a *Action) (*Job, *http.Response, error) {
+	if err := r.supported(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := r.client.NewRequest("POST", fmt.Sprintf(respondersURL+"/%s/run", rid), a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var j Job
+	resp, err := r.client.Do(ctx, req, &j)
+	if err != nil {
+		return nil, resp, unsupportedError(err)
+	}
+
+	return &j, resp, nil
+}
+
+// supported returns an error matching ErrUnsupported if the server doesn't
+// support responders
+func (r *ResponderServiceOp) supported(ctx context.Context) error {
+	return r.client.supports(ctx, "responders", func(c *Capabilities) bool {
+		return c.Responders
+	})
+}
@@ -0,0 +1,121 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListByTypeResponders(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+respondersByType+"thehive:case", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(respondersJSON)
+	})
+
+	got, _, err := client.Responders.ListByType(context.Background(), "thehive:case")
+	if err != nil {
+		t.Errorf("Responders.ListByType returned error: %v", err)
+	}
+	if want := wantResponders; !reflect.DeepEqual(got, want) {
+		t.Errorf("Responders.ListByType = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunResponder(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+respondersURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(respondersJSON)
+	})
+	mux.HandleFunc("/"+respondersURL+"/5b1c4c47a0e9d3f6c5e8b2a1cd7f4e96", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wantResponders[0])
+	})
+	mux.HandleFunc("/"+respondersURL+"/5b1c4c47a0e9d3f6c5e8b2a1cd7f4e96/run", func(w http.ResponseWriter, r *http.Request) {
+		var a map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a["dataType"] != "thehive:case" {
+			t.Errorf("dataType = %v, want thehive:case", a["dataType"])
+		}
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui9"}`))
+	})
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui9/waitreport", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(actionReportJSON)
+	})
+
+	got, err := client.Responders.Run(context.Background(), "Mailer_1_0", &Action{
+		Data:     map[string]interface{}{"caseId": 1, "title": "test"},
+		DataType: "thehive:case",
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("Responders.Run returned error: %v", err)
+	}
+	if want := wantOperations; !reflect.DeepEqual(got.Operations(), want) {
+		t.Errorf("ActionReport.Operations = %+v, want %+v", got.Operations(), want)
+	}
+}
+
+var respondersJSON = []byte(`
+[
+  {
+    "id": "5b1c4c47a0e9d3f6c5e8b2a1cd7f4e96",
+    "name": "Mailer_1_0",
+    "version": "1.0",
+    "description": "Send an email with information from a TheHive case or alert",
+    "author": "CERT-BDF",
+    "url": "https://github.com/TheHive-Project/Cortex-Analyzers",
+    "license": "AGPL-V3",
+    "baseConfig": "Mailer",
+    "responderDefinitionId": "Mailer_1_0",
+    "dataTypeList": ["thehive:case", "thehive:alert"],
+    "configuration": {},
+    "createdAt": 1527178197172,
+    "createdBy": "test1"
+  }
+]`)
+
+var wantResponders = []Responder{
+	{
+		Author:        "CERT-BDF",
+		BaseConfig:    "Mailer",
+		Configuration: map[string]interface{}{},
+		CreatedAt:     1527178197172,
+		CreatedBy:     "test1",
+		DataTypeList:  []string{"thehive:case", "thehive:alert"},
+		DefinitionID:  "Mailer_1_0",
+		Description:   "Send an email with information from a TheHive case or alert",
+		ID:            "5b1c4c47a0e9d3f6c5e8b2a1cd7f4e96",
+		License:       "AGPL-V3",
+		Name:          "Mailer_1_0",
+		URL:           "https://github.com/TheHive-Project/Cortex-Analyzers",
+		Version:       "1.0",
+	},
+}
+
+var actionReportJSON = []byte(`
+{
+  "id": "AWOsZ3pPqNgGAnpM4Ui9",
+  "status": "Success",
+  "dataType": "thehive:case",
+  "report": {
+    "success": true,
+    "full": {"message": "message sent"},
+    "operations": [
+      {"type": "AddTagToCase", "tag": "mail sent"},
+      {"type": "AddCustomFields", "name": "notified", "tpe": "boolean", "value": true}
+    ]
+  }
+}`)
+
+var wantOperations = []Operation{
+	{Type: "AddTagToCase", Tag: "mail sent"},
+	{Type: "AddCustomFields", Name: "notified", Tpe: "boolean", Value: true},
+}
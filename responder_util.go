@@ -0,0 +1,159 @@
+package cortex
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+// ResponderInput is used to work with responder's input
+type ResponderInput struct {
+	DataType   string            `json:"dataType"`
+	TLP        TLP               `json:"tlp,omitempty"`
+	PAP        PAP               `json:"pap,omitempty"`
+	Data       json.RawMessage   `json:"data,omitempty"`
+	Config     cfg               `json:"config,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// ResponderReport is the report that responder app should return in case
+// everything is okay
+type ResponderReport struct {
+	FullReport interface{} `json:"full"`
+	Success    bool        `json:"success"`
+	Operations []Operation `json:"operations"`
+}
+
+// ResponderError is the report that responder app should return in case
+// something went wrong
+type ResponderError struct {
+	Success      bool            `json:"success"`
+	ErrorMessage string          `json:"errorMessage"`
+	Input        *ResponderInput `json:"input"`
+}
+
+// NewResponderInput grabs DefaultInput (stdin by default) and bootstraps
+// *ResponderInput and *http.Client
+func NewResponderInput() (*ResponderInput, *http.Client, error) {
+	return newResponderInput(DefaultInput)
+}
+
+func newResponderInput(r io.Reader) (*ResponderInput, *http.Client, error) {
+	var in ResponderInput
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, http.DefaultClient, err
+	}
+
+	if err := in.Config.checkSharing(in.TLP, in.PAP); err != nil {
+		in.PrintError(err)
+	}
+
+	return &in, in.Config.httpClient(), nil
+}
+
+// DecodeData unmarshals the responder's data, which is a TheHive object
+// (case, alert, case task, case artifact), into v
+func (r *ResponderInput) DecodeData(v interface{}) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+// PrintError returns unsuccessful responder report with an error message
+func (r *ResponderInput) PrintError(err error) {
+	if werr := r.WriteError(DefaultOutput, err); werr != nil {
+		log.Fatal(werr)
+	}
+	os.Exit(1)
+}
+
+// PrintReport constructs responder report by raw body and operations
+func (r *ResponderInput) PrintReport(body interface{}, ops []Operation) {
+	if err := r.WriteReport(DefaultOutput, body, ops); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(0)
+}
+
+// WriteError writes unsuccessful responder report with an error message to w
+func (r *ResponderInput) WriteError(w io.Writer, err error) error {
+	return writeJSON(w, &ResponderError{
+		Success:      false,
+		ErrorMessage: err.Error(),
+		Input:        r,
+	})
+}
+
+// WriteReport writes responder report constructed by raw body and
+// operations to w
+func (r *ResponderInput) WriteReport(w io.Writer, body interface{}, ops []Operation) error {
+	return writeJSON(w, newResponderReport(body, ops))
+}
+
+func newResponderReport(body interface{}, ops []Operation) *ResponderReport {
+	if body == nil {
+		body = struct{}{}
+	}
+
+	if ops == nil {
+		ops = []Operation{}
+	}
+
+	return &ResponderReport{
+		Success:    true,
+		FullReport: body,
+		Operations: ops,
+	}
+}
+
+// AddTagToCase returns an operation that adds a tag to the case
+func AddTagToCase(tag string) Operation {
+	return Operation{Type: "AddTagToCase", Tag: tag}
+}
+
+// AddTagToArtifact returns an operation that adds a tag to the artifact
+func AddTagToArtifact(tag string) Operation {
+	return Operation{Type: "AddTagToArtifact", Tag: tag}
+}
+
+// AddTagToAlert returns an operation that adds a tag to the alert
+func AddTagToAlert(tag string) Operation {
+	return Operation{Type: "AddTagToAlert", Tag: tag}
+}
+
+// MarkAlertAsRead returns an operation that marks the alert as read
+func MarkAlertAsRead() Operation {
+	return Operation{Type: "MarkAlertAsRead"}
+}
+
+// AddCustomFields returns an operation that sets the case custom field.
+// tpe is a custom field type, e.g. "string", "number", "boolean" or "date".
+func AddCustomFields(name, tpe string, value interface{}) Operation {
+	return Operation{Type: "AddCustomFields", Name: name, Tpe: tpe, Value: value}
+}
+
+// CreateTask returns an operation that creates a task in the case
+func CreateTask(title, description string) Operation {
+	return Operation{Type: "CreateTask", Title: title, Description: description}
+}
+
+// AddLogToTask returns an operation that adds a log to the task
+func AddLogToTask(content, owner string) Operation {
+	return Operation{Type: "AddLogToTask", Content: content, Owner: owner}
+}
+
+// AddArtifactToCase returns an operation that adds an artifact to the case
+func AddArtifactToCase(data, dataType, message string) Operation {
+	return Operation{Type: "AddArtifactToCase", Data: data, DataType: dataType, Message: message}
+}
+
+// AssignCase returns an operation that assigns the case to the owner
+func AssignCase(owner string) Operation {
+	return Operation{Type: "AssignCase", Owner: owner}
+}
+
+// CloseTask returns an operation that closes the task
+func CloseTask() Operation {
+	return Operation{Type: "CloseTask"}
+}
@@ -0,0 +1,65 @@
+package cortex
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var sampleResponderInput = []byte(`
+{
+    "data": {"caseId": 42, "title": "Phishing campaign", "tags": ["phishing"]},
+    "dataType": "thehive:case",
+    "tlp": 2,
+    "pap": 2,
+    "config": {
+        "max_tlp": 3,
+        "max_pap": 2,
+        "check_tlp": true,
+        "check_pap": true
+    }
+}
+`)
+
+func TestResponderInput(t *testing.T) {
+	in, _, err := newResponderInput(bytes.NewReader(sampleResponderInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c struct {
+		CaseID int    `json:"caseId"`
+		Title  string `json:"title"`
+	}
+	if err := in.DecodeData(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.CaseID != 42 || c.Title != "Phishing campaign" {
+		t.Fatalf("need case 42, got %+v", c)
+	}
+
+	if err := in.Config.checkPAP(PAPRed); err != errTooHighPAP {
+		t.Fatalf("need %v, got %v", errTooHighPAP, err)
+	}
+}
+
+func TestResponderReport(t *testing.T) {
+	r := newResponderReport(nil, []Operation{
+		AddTagToCase("mail sent"),
+		MarkAlertAsRead(),
+		AddCustomFields("notified", "boolean", true),
+	})
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"full":{},"success":true,"operations":[` +
+		`{"type":"AddTagToCase","tag":"mail sent"},` +
+		`{"type":"MarkAlertAsRead"},` +
+		`{"type":"AddCustomFields","name":"notified","value":true,"tpe":"boolean"}]}`
+	if string(b) != want {
+		t.Fatalf("need %s, got %s", want, b)
+	}
+}
@@ -0,0 +1,158 @@
+package cortex
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures retries of rate-limited and transiently failed
+// requests.
+//
+// Requests with idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) are
+// retried on network errors and on 429, 502, 503 and 504 responses.
+// Other requests, e.g. a POST that starts a job, are retried only on 429 and
+// 503 responses, which mean that Cortex hasn't processed the request, so the
+// job is never started twice.
+type RetryPolicy struct {
+	// MaxAttempts is a maximum number of attempts including the first one
+	MaxAttempts int
+
+	// MinBackoff is a delay before the first retry, it is doubled on every
+	// next one. Defaults to 500ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent enables retries of non-idempotent requests on the
+	// same conditions as idempotent ones. Use it only if duplicate jobs are
+	// acceptable.
+	RetryNonIdempotent bool
+}
+
+// sendWithRetry sends the request retrying it according to the client's
+// RetryPolicy
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	p := c.Opts.Retry
+
+	r := req
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendAuthenticated(r)
+		if p == nil || attempt >= p.MaxAttempts || !p.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		next, rerr := rewind(req)
+		if rerr != nil {
+			return resp, err
+		}
+
+		wait := p.backoff(attempt, resp)
+		if resp != nil {
+			io.CopyN(io.Discard, resp.Body, 4096)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+
+		r = next
+	}
+}
+
+// retryable decides if the request should be sent again
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	idempotent := p.RetryNonIdempotent
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		idempotent = true
+	}
+
+	if err != nil {
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+
+	return false
+}
+
+// backoff returns a delay before the next attempt. Retry-After header takes
+// precedence over exponential backoff with jitter, both are capped by
+// MaxBackoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > max {
+				d = max
+			}
+			return d
+		}
+	}
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// equal jitter: at least a half of the delay plus a random part
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter parses Retry-After header value, which is either seconds or
+// an HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
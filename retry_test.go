@@ -0,0 +1,104 @@
+package cortex
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var tests = []struct {
+		method   string
+		statuses []int
+		attempts int
+		success  bool
+	}{
+		{"GET", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 3, true},
+		{"GET", []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}, 3, false},
+		{"GET", []int{http.StatusInternalServerError, http.StatusOK}, 1, false},
+		{"POST", []int{http.StatusTooManyRequests, http.StatusOK}, 2, true},
+		{"POST", []int{http.StatusBadGateway, http.StatusOK}, 1, false},
+	}
+
+	for _, tt := range tests {
+		client, mux, _, closer := setup()
+		client.Opts.Retry = &RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  time.Millisecond,
+		}
+
+		var attempts int
+		mux.HandleFunc("/"+jobsURL, func(w http.ResponseWriter, r *http.Request) {
+			if tt.method == "POST" {
+				var b bytes.Buffer
+				b.ReadFrom(r.Body)
+				if b.String() != "{\"data\":\"1.1.1.1\"}\n" {
+					t.Errorf("attempt %d body = %q", attempts+1, b.String())
+				}
+			}
+
+			status := tt.statuses[attempts]
+			attempts++
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(status)
+			w.Write([]byte("[]"))
+		})
+
+		var body interface{}
+		if tt.method == "POST" {
+			body = &Task{Data: "1.1.1.1"}
+		}
+		req, err := client.NewRequest(tt.method, jobsURL, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.Do(context.Background(), req, nil)
+		if tt.success != (err == nil) {
+			t.Errorf("%s %v: unexpected error %v", tt.method, tt.statuses, err)
+		}
+		if attempts != tt.attempts {
+			t.Errorf("%s %v: made %d attempts, want %d", tt.method, tt.statuses, attempts, tt.attempts)
+		}
+
+		closer()
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var tests = []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoffCap(t *testing.T) {
+	p := &RetryPolicy{MaxBackoff: time.Minute}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"86400"}}}
+
+	if got := p.backoff(1, resp); got != time.Minute {
+		t.Errorf("backoff = %v, want Retry-After capped to %v", got, time.Minute)
+	}
+	if got := p.backoff(10, nil); got > time.Minute {
+		t.Errorf("backoff = %v, want at most %v", got, time.Minute)
+	}
+}
@@ -0,0 +1,221 @@
+package cortex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const statusURL = APIRoute + "/status"
+
+// Status represents the server status returned by /api/status
+type Status struct {
+	// Versions maps a component name, e.g. Cortex or Elastic4Play, to its
+	// version
+	Versions map[string]string `json:"versions"`
+	Config   StatusConfig      `json:"config"`
+}
+
+// StatusConfig represents the server configuration flags
+type StatusConfig struct {
+	AuthType             []string `json:"authType"`
+	Capabilities         []string `json:"capabilities"`
+	ProtectDownloadsWith string   `json:"protectDownloadsWith,omitempty"`
+	SSOAutoLogin         bool     `json:"ssoAutoLogin"`
+}
+
+// Version returns the Cortex version
+func (s *Status) Version() string {
+	return s.Versions["Cortex"]
+}
+
+// Capabilities describes features supported by the server
+type Capabilities struct {
+	// Version is the Cortex version, it's empty if the server doesn't
+	// report its status
+	Version string
+	Major   int
+	Minor   int
+
+	AuthTypes []string
+
+	// Responders are supported since Cortex 2
+	Responders bool
+
+	// AnalyzerDefinitions are supported since Cortex 2
+	AnalyzerDefinitions bool
+
+	// AbortJob is supported since Cortex 3, it's also switched off once
+	// the server turns out not to support it
+	AbortJob bool
+
+	// AuthByKey, ChangePassword and SetPassword depend on the server
+	// authentication providers
+	AuthByKey      bool
+	ChangePassword bool
+	SetPassword    bool
+}
+
+// Status retrieves the server version and configuration
+func (c *Client) Status(ctx context.Context) (*Status, *http.Response, error) {
+	req, err := c.NewRequest("GET", statusURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var s Status
+	resp, err := c.Do(ctx, req, &s)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &s, resp, nil
+}
+
+// capsCall is a capabilities detection shared by concurrent callers
+type capsCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Capabilities returns features supported by the server. They are detected
+// by Status once and cached. If the server doesn't report its status, all
+// features are assumed to be supported and the Version is empty. Other
+// errors, e.g. failed authentication or rate limiting, aren't cached, so the
+// next call tries again.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	for {
+		c.capsMu.Lock()
+		if c.caps != nil {
+			caps := *c.caps
+			c.capsMu.Unlock()
+			return &caps, nil
+		}
+
+		call, leader := c.capsCall, false
+		if call == nil {
+			call, leader = &capsCall{done: make(chan struct{})}, true
+			c.capsCall = call
+		}
+		c.capsMu.Unlock()
+
+		if leader {
+			c.detectCapabilities(ctx, call)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		switch {
+		case call.err == nil:
+			// the capabilities are cached now
+		case !leader && isContextError(call.err):
+			// the caller detecting them has gone, so try on our own
+		default:
+			return nil, call.err
+		}
+	}
+}
+
+// detectCapabilities requests the server status and caches capabilities
+// derived from it unless the error is transient
+func (c *Client) detectCapabilities(ctx context.Context, call *capsCall) {
+	var caps *Capabilities
+	s, _, err := c.Status(ctx)
+	var er *ErrorResponse
+	switch {
+	case err == nil:
+		caps = newCapabilities(s)
+	case errors.As(err, &er) && er.StatusCode == http.StatusNotFound:
+		caps, err = newCapabilities(&Status{}), nil
+	}
+
+	c.capsMu.Lock()
+	if err == nil {
+		c.caps = caps
+	}
+	c.capsCall = nil
+	c.capsMu.Unlock()
+
+	call.err = err
+	close(call.done)
+}
+
+// isContextError reports whether err is caused by a cancelled context or
+// its deadline
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// supports returns an error matching ErrUnsupported if the server doesn't
+// support a feature checked by ok. If capabilities can't be detected, the
+// feature is assumed to be supported, so the caller makes the request and
+// maps its failure with unsupportedError.
+func (c *Client) supports(ctx context.Context, feature string, ok func(*Capabilities) bool) error {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return nil
+	}
+
+	if !ok(caps) {
+		return fmt.Errorf("%s: %w", feature, ErrUnsupported)
+	}
+
+	return nil
+}
+
+// unsupported switches off a feature detected as unsupported by a request
+func (c *Client) unsupported(off func(*Capabilities)) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+
+	if c.caps != nil {
+		off(c.caps)
+	}
+}
+
+// newCapabilities derives features from the server status, an unknown
+// version supports everything
+func newCapabilities(s *Status) *Capabilities {
+	caps := &Capabilities{
+		Version:   s.Version(),
+		AuthTypes: s.Config.AuthType,
+	}
+	caps.Major, caps.Minor = parseVersion(caps.Version)
+
+	since := func(major int) bool {
+		return caps.Major == 0 || caps.Major >= major
+	}
+	caps.Responders = since(2)
+	caps.AnalyzerDefinitions = since(2)
+	caps.AbortJob = since(3)
+
+	has := func(name string) bool {
+		if s.Config.Capabilities == nil {
+			return true
+		}
+		return contains(s.Config.Capabilities, name)
+	}
+	caps.AuthByKey = has("authByKey")
+	caps.ChangePassword = has("changePassword")
+	caps.SetPassword = has("setPassword")
+
+	return caps
+}
+
+// parseVersion parses major and minor numbers of a version like 3.1.1-1
+func parseVersion(v string) (major, minor int) {
+	parts := strings.SplitN(v, ".", 3)
+	major, _ = strconv.Atoi(parts[0])
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+
+	return major, minor
+}
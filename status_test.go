@@ -0,0 +1,210 @@
+package cortex
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+statusURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(statusJSON)
+	})
+
+	got, _, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Client.Status returned error: %v", err)
+	}
+
+	want := &Status{
+		Versions: map[string]string{
+			"Cortex":       "3.1.1-1",
+			"Elastic4Play": "1.13.1",
+		},
+		Config: StatusConfig{
+			AuthType:             []string{"key", "local"},
+			Capabilities:         []string{"authByKey", "changePassword", "setPassword"},
+			ProtectDownloadsWith: "malware",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Status = %+v, want %+v", got, want)
+	}
+	if got.Version() != "3.1.1-1" {
+		t.Errorf("Status.Version = %s, want 3.1.1-1", got.Version())
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	var tests = []struct {
+		status []byte
+		want   *Capabilities
+	}{
+		{statusJSON, &Capabilities{
+			Version:             "3.1.1-1",
+			Major:               3,
+			Minor:               1,
+			AuthTypes:           []string{"key", "local"},
+			Responders:          true,
+			AnalyzerDefinitions: true,
+			AbortJob:            true,
+			AuthByKey:           true,
+			ChangePassword:      true,
+			SetPassword:         true,
+		}},
+		{[]byte(`{"versions":{"Cortex":"2.1.3-1"},"config":{"authType":["key"],"capabilities":["authByKey"]}}`), &Capabilities{
+			Version:             "2.1.3-1",
+			Major:               2,
+			Minor:               1,
+			AuthTypes:           []string{"key"},
+			Responders:          true,
+			AnalyzerDefinitions: true,
+			AuthByKey:           true,
+		}},
+		{nil, &Capabilities{
+			Responders:          true,
+			AnalyzerDefinitions: true,
+			AbortJob:            true,
+			AuthByKey:           true,
+			ChangePassword:      true,
+			SetPassword:         true,
+		}},
+	}
+
+	for i, tt := range tests {
+		client, mux, _, closer := setup()
+
+		var requested int
+		status := tt.status
+		mux.HandleFunc("/"+statusURL, func(w http.ResponseWriter, r *http.Request) {
+			requested++
+			if status == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(status)
+		})
+
+		for j := 0; j < 2; j++ {
+			got, err := client.Capabilities(context.Background())
+			if err != nil {
+				t.Fatalf("%d: Client.Capabilities returned error: %v", i, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%d: Client.Capabilities = %+v, want %+v", i, got, tt.want)
+			}
+		}
+		if requested != 1 {
+			t.Errorf("%d: status is requested %d times, want once", i, requested)
+		}
+
+		closer()
+	}
+}
+
+func TestCapabilitiesRetry(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var requested int32
+	release := make(chan struct{})
+	mux.HandleFunc("/"+statusURL, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requested, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+		w.Write(statusJSON)
+	})
+
+	if _, err := client.Capabilities(context.Background()); err == nil {
+		t.Fatal("Client.Capabilities returned no error for unavailable server")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			caps, err := client.Capabilities(context.Background())
+			if err != nil || caps.Version != "3.1.1-1" {
+				t.Errorf("Client.Capabilities = %+v, %v", caps, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requested); n != 2 {
+		t.Errorf("status is requested %d times, want twice", n)
+	}
+}
+
+func TestSupportsUndetected(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+statusURL, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/"+respondersURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/"+analyzerDefinitionsURL, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	// the endpoints are requested even though capabilities aren't detected
+	if _, _, err := client.Responders.List(context.Background()); err != nil {
+		t.Errorf("Responders.List returned error: %v", err)
+	}
+	if _, _, err := client.Analyzers.Definitions(context.Background()); !IsUnsupported(err) {
+		t.Errorf("Analyzers.Definitions returned %v, want %v", err, ErrUnsupported)
+	}
+}
+
+func TestAbortUnsupported(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var aborts int
+	mux.HandleFunc("/"+statusURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(statusJSON)
+	})
+	mux.HandleFunc("/"+jobsURL+"/AWOsZ3pPqNgGAnpM4Ui0/abort", func(w http.ResponseWriter, r *http.Request) {
+		aborts++
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Jobs.Abort(context.Background(), "AWOsZ3pPqNgGAnpM4Ui0")
+		if !IsUnsupported(err) {
+			t.Errorf("Jobs.Abort returned %v, want %v", err, ErrUnsupported)
+		}
+	}
+	if aborts != 1 {
+		t.Errorf("abort is requested %d times, want once", aborts)
+	}
+}
+
+var statusJSON = []byte(`
+{
+  "versions": {
+    "Cortex": "3.1.1-1",
+    "Elastic4Play": "1.13.1"
+  },
+  "config": {
+    "protectDownloadsWith": "malware",
+    "authType": ["key", "local"],
+    "capabilities": ["authByKey", "changePassword", "setPassword"],
+    "ssoAutoLogin": false
+  }
+}`)
@@ -0,0 +1,109 @@
+package cortex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaxonomyLevel is a severity level of a taxonomy
+type TaxonomyLevel string
+
+const (
+	// TxSafe is a safe taxonomy level
+	TxSafe TaxonomyLevel = "safe"
+
+	// TxInfo is an info taxonomy level
+	TxInfo TaxonomyLevel = "info"
+
+	// TxSuspicious is a suspicious taxonomy level
+	TxSuspicious TaxonomyLevel = "suspicious"
+
+	// TxMalicious is a malicious taxonomy level
+	TxMalicious TaxonomyLevel = "malicious"
+)
+
+// levelRanks orders taxonomy levels from the least to the most severe
+var levelRanks = map[TaxonomyLevel]int{
+	TxSafe:       1,
+	TxInfo:       2,
+	TxSuspicious: 3,
+	TxMalicious:  4,
+}
+
+// ParseTaxonomyLevel parses a taxonomy level case-insensitively
+func ParseTaxonomyLevel(s string) (TaxonomyLevel, error) {
+	l := TaxonomyLevel(strings.ToLower(s))
+	if !l.Valid() {
+		return "", fmt.Errorf("unknown taxonomy level %q", s)
+	}
+
+	return l, nil
+}
+
+// Rank returns the level severity: safe < info < suspicious < malicious.
+// It returns zero for an unknown level.
+func (l TaxonomyLevel) Rank() int {
+	return levelRanks[l]
+}
+
+// Valid reports whether the level is known
+func (l TaxonomyLevel) Valid() bool {
+	return l.Rank() > 0
+}
+
+// Less reports whether the level is less severe than o
+func (l TaxonomyLevel) Less(o TaxonomyLevel) bool {
+	return l.Rank() < o.Rank()
+}
+
+// Taxonomy represents a taxonomy object in a report
+type Taxonomy struct {
+	Predicate string        `json:"predicate"`
+	Namespace string        `json:"namespace"`
+	Value     interface{}   `json:"value"`
+	Level     TaxonomyLevel `json:"level"`
+}
+
+// String formats the taxonomy as TheHive tag: namespace:predicate="value"
+func (t Taxonomy) String() string {
+	s := t.Namespace + ":" + t.Predicate
+	if t.Value == nil {
+		return s
+	}
+
+	return s + "=" + strconv.Quote(fmt.Sprint(t.Value))
+}
+
+// ParseTaxonomy parses a taxonomy in TheHive tag form returned by
+// Taxonomy.String. The value is a string, the level is unknown.
+func ParseTaxonomy(s string) (Taxonomy, error) {
+	var t Taxonomy
+
+	i := strings.Index(s, ":")
+	if i <= 0 {
+		return t, fmt.Errorf("no namespace in taxonomy %q", s)
+	}
+	t.Namespace = s[:i]
+	t.Predicate = s[i+1:]
+
+	if i := strings.Index(t.Predicate, "="); i >= 0 {
+		v := t.Predicate[i+1:]
+		t.Predicate = t.Predicate[:i]
+
+		if strings.HasPrefix(v, `"`) {
+			uv, err := strconv.Unquote(v)
+			if err != nil {
+				return t, fmt.Errorf("invalid value in taxonomy %q: %w", s, err)
+			}
+			v = uv
+		}
+		t.Value = v
+	}
+
+	if t.Predicate == "" {
+		return t, fmt.Errorf("no predicate in taxonomy %q", s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,83 @@
+package cortex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaxonomyLevel(t *testing.T) {
+	var tests = []struct {
+		in    string
+		level TaxonomyLevel
+		valid bool
+	}{
+		{"safe", TxSafe, true},
+		{"Info", TxInfo, true},
+		{"SUSPICIOUS", TxSuspicious, true},
+		{"malicious", TxMalicious, true},
+		{"harmless", "", false},
+	}
+
+	for _, tt := range tests {
+		l, err := ParseTaxonomyLevel(tt.in)
+		if (err == nil) != tt.valid || l != tt.level {
+			t.Errorf("ParseTaxonomyLevel(%q) = %q, %v, want %q", tt.in, l, err, tt.level)
+		}
+	}
+
+	if !TxInfo.Less(TxSuspicious) || TxMalicious.Less(TxSafe) || !TaxonomyLevel("").Less(TxSafe) {
+		t.Error("taxonomy levels are misordered")
+	}
+}
+
+func TestTaxonomyString(t *testing.T) {
+	var tests = []struct {
+		tx Taxonomy
+		s  string
+	}{
+		{Taxonomy{Namespace: "MaxMind", Predicate: "Location", Value: "Australia"}, `MaxMind:Location="Australia"`},
+		{Taxonomy{Namespace: "VT", Predicate: "GetReport", Value: `0/66 "clean"`}, `VT:GetReport="0/66 \"clean\""`},
+		{Taxonomy{Namespace: "Shodan", Predicate: "Host"}, `Shodan:Host`},
+	}
+
+	for _, tt := range tests {
+		if s := tt.tx.String(); s != tt.s {
+			t.Errorf("Taxonomy.String() = %s, want %s", s, tt.s)
+		}
+
+		tx, err := ParseTaxonomy(tt.s)
+		if err != nil {
+			t.Errorf("ParseTaxonomy(%s) returned error: %v", tt.s, err)
+		}
+		if !reflect.DeepEqual(tx, tt.tx) {
+			t.Errorf("ParseTaxonomy(%s) = %+v, want %+v", tt.s, tx, tt.tx)
+		}
+	}
+
+	for _, s := range []string{"Location", ":Location", "MaxMind:", `MaxMind:Location="Australia`} {
+		if _, err := ParseTaxonomy(s); err == nil {
+			t.Errorf("ParseTaxonomy(%s) returned no error", s)
+		}
+	}
+}
+
+func TestReportTaxonomies(t *testing.T) {
+	r := &Report{ReportBody: ReportBody{Summary: Summary{Taxonomies: []Taxonomy{
+		{Namespace: "MaxMind", Predicate: "Location", Value: "Russia", Level: TxInfo},
+		{Namespace: "VT", Predicate: "GetReport", Value: "42/66", Level: TxMalicious},
+		{Namespace: "MaxMind", Predicate: "Blacklist", Value: "hit", Level: TxSuspicious},
+	}}}}
+
+	if l := r.MaxLevel(); l != TxMalicious {
+		t.Errorf("Report.MaxLevel() = %s, want %s", l, TxMalicious)
+	}
+	if !r.IsMalicious() {
+		t.Error("Report.IsMalicious() = false, want true")
+	}
+	if txs := r.ByNamespace(); len(txs["MaxMind"]) != 2 || len(txs["VT"]) != 1 {
+		t.Errorf("Report.ByNamespace() = %+v", txs)
+	}
+	if (&Report{}).IsMalicious() {
+		t.Error("empty report is malicious")
+	}
+}
@@ -0,0 +1,242 @@
+package cortex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+// notFoundPolls is a number of consecutive polls a job must be missing from
+// to be reported as not found, since a new job may be not searchable yet
+const notFoundPolls = 3
+
+// TrackedReport is delivered by JobTracker once a job is finished, failed
+// or passed its deadline
+type TrackedReport struct {
+	JobID  string
+	Report *Report
+	Err    error
+}
+
+// JobTracker polls statuses of many jobs at once with a single search
+// request per batch instead of waiting for each job report separately
+type JobTracker struct {
+	// Interval between polls, defaults to 5 seconds
+	Interval time.Duration
+
+	// Deadline is a maximum time to wait for each job counted from Add,
+	// zero means no deadline
+	Deadline time.Duration
+
+	// OnReport is called for every finished job. If it's nil reports are
+	// sent to the channel returned by C, which must be drained, otherwise
+	// polling is blocked.
+	OnReport func(*TrackedReport)
+
+	// OnError is called for every failed poll, the jobs are polled again
+	// on the next tick. If it's nil errors are ignored.
+	OnError func(error)
+
+	jobs *JobServiceOp
+	c    chan *TrackedReport
+
+	mu      sync.Mutex
+	pending map[string]time.Time
+	missing map[string]int
+	ran     bool
+}
+
+// NewTracker bootstraps a JobTracker with a poll interval and a deadline
+// per job
+func (j *JobServiceOp) NewTracker(interval, deadline time.Duration) *JobTracker {
+	return &JobTracker{
+		Interval: interval,
+		Deadline: deadline,
+		jobs:     j,
+		c:        make(chan *TrackedReport),
+		pending:  make(map[string]time.Time),
+		missing:  make(map[string]int),
+	}
+}
+
+// Add job IDs to track, e.g. returned by AnalyzerService.StartJob
+func (t *JobTracker) Add(ids ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		t.pending[id] = now
+	}
+}
+
+// Pending returns a number of tracked jobs that are not finished yet
+func (t *JobTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.pending)
+}
+
+// C returns a channel with finished jobs, which is closed when Run returns.
+// Unless OnReport is set, the channel must be drained while Run is polling,
+// since every report blocks polling until it's received.
+func (t *JobTracker) C() <-chan *TrackedReport {
+	return t.c
+}
+
+// Run polls job statuses until the context is done. A tracker can be run
+// only once, then its channel is closed.
+func (t *JobTracker) Run(ctx context.Context) error {
+	t.mu.Lock()
+	ran := t.ran
+	t.ran = true
+	t.mu.Unlock()
+	if ran {
+		return errors.New("job tracker has already run")
+	}
+	defer close(t.c)
+
+	interval := t.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// a failed poll is not fatal, the jobs are polled again on the next
+		// tick
+		if err := t.poll(ctx); err != nil && ctx.Err() == nil && t.OnError != nil {
+			t.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll searches all pending jobs in batches and delivers finished ones
+func (t *JobTracker) poll(ctx context.Context) error {
+	t.mu.Lock()
+	ids := make([]interface{}, 0, len(t.pending))
+	var expired []string
+	for id, added := range t.pending {
+		if t.Deadline > 0 && time.Since(added) > t.Deadline {
+			expired = append(expired, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	t.mu.Unlock()
+
+	for _, id := range expired {
+		t.deliver(ctx, &TrackedReport{
+			JobID: id,
+			Err:   fmt.Errorf("job %s passed its deadline %s: %w", id, t.Deadline, ErrTimeout),
+		})
+	}
+
+	size := t.jobs.client.PageSize
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		if err := t.pollBatch(ctx, ids[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *JobTracker) pollBatch(ctx context.Context, ids []interface{}) error {
+	jobs, _, err := t.jobs.Search(ctx, &SearchOpts{
+		Query: In("_id", ids...),
+		Range: fmt.Sprintf("0-%d", len(ids)),
+	})
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		found[j.ID] = true
+
+		switch j.Status {
+		case "Success", "Failure":
+			r, _, err := t.jobs.GetReport(ctx, j.ID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				t.deliver(ctx, &TrackedReport{JobID: j.ID, Err: err})
+				continue
+			}
+
+			tr := &TrackedReport{JobID: j.ID, Report: r}
+			if j.Status == "Failure" {
+				tr.Err = fmt.Errorf("job %s failed: %s", j.ID, r.ReportBody.ErrorMessage)
+			}
+			t.deliver(ctx, tr)
+		case "Deleted":
+			t.deliver(ctx, &TrackedReport{
+				JobID: j.ID,
+				Err:   fmt.Errorf("job %s is deleted", j.ID),
+			})
+		}
+	}
+
+	var notFound []string
+	t.mu.Lock()
+	for _, id := range ids {
+		id := id.(string)
+		if found[id] {
+			delete(t.missing, id)
+			continue
+		}
+
+		t.missing[id]++
+		if t.missing[id] >= notFoundPolls {
+			notFound = append(notFound, id)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, id := range notFound {
+		t.deliver(ctx, &TrackedReport{
+			JobID: id,
+			Err:   fmt.Errorf("job %s: %w", id, ErrNotFound),
+		})
+	}
+
+	return nil
+}
+
+// deliver removes the job from pending ones and hands the report over
+func (t *JobTracker) deliver(ctx context.Context, tr *TrackedReport) {
+	t.mu.Lock()
+	delete(t.pending, tr.JobID)
+	delete(t.missing, tr.JobID)
+	t.mu.Unlock()
+
+	if t.OnReport != nil {
+		t.OnReport(tr)
+		return
+	}
+
+	select {
+	case t.c <- tr:
+	case <-ctx.Done():
+	}
+}
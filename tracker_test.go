@@ -0,0 +1,86 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJobTracker(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	mux.HandleFunc("/"+jobsSearchURL, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		n := polls
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			// job5 isn't searchable yet
+			w.Write([]byte(`[{"id":"job1","status":"Success"},{"id":"job2","status":"InProgress"},{"id":"job3","status":"Failure"}]`))
+		default:
+			w.Write([]byte(`[{"id":"job2","status":"Success"},{"id":"job5","status":"Success"}]`))
+		}
+	})
+	for _, id := range []string{"job1", "job2", "job3", "job5"} {
+		id := id
+		mux.HandleFunc("/"+jobsURL+"/"+id+"/report", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id":"%s","report":{"success":%t,"errorMessage":"boom"}}`, id, id != "job3")
+		})
+	}
+
+	tracker := client.Jobs.NewTracker(10*time.Millisecond, time.Minute)
+	var errs []error
+	tracker.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+	tracker.Add("job1", "job2", "job3", "job4", "job5")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go tracker.Run(ctx)
+
+	got := make(map[string]*TrackedReport)
+	for tr := range tracker.C() {
+		got[tr.JobID] = tr
+		if len(got) == 5 {
+			cancel()
+		}
+	}
+
+	if r := got["job1"]; r == nil || r.Err != nil || !r.Report.ReportBody.Success {
+		t.Errorf("job1 = %+v, want successful report", r)
+	}
+	if r := got["job2"]; r == nil || r.Err != nil || r.Report.ID != "job2" {
+		t.Errorf("job2 = %+v, want successful report", r)
+	}
+	if r := got["job3"]; r == nil || r.Err == nil {
+		t.Errorf("job3 = %+v, want failure", r)
+	}
+	if r := got["job4"]; r == nil || !IsNotFound(r.Err) {
+		t.Errorf("job4 = %+v, want not found error", r)
+	}
+	if r := got["job5"]; r == nil || r.Err != nil {
+		t.Errorf("job5 = %+v, want successful report", r)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got poll errors %v, want one", errs)
+	}
+	if tracker.Pending() != 0 {
+		t.Errorf("%d jobs are still pending", tracker.Pending())
+	}
+	if err := tracker.Run(context.Background()); err == nil {
+		t.Error("tracker is run twice")
+	}
+}
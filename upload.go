@@ -0,0 +1,195 @@
+package cortex
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+	"sync"
+)
+
+// upload streams a multipart form with a file attachment and a JSON meta
+// data, which is what Cortex expects to run a file analysis
+type upload struct {
+	fileName string
+	r        io.Reader
+	meta     []byte
+	boundary string
+
+	// size is a size of the whole form or -1 if it is unknown
+	size int64
+
+	// seekable is true when r could be rewound to start
+	seekable bool
+	start    int64
+
+	mu  sync.Mutex
+	cur *uploadBody
+}
+
+func newUpload(fileName string, r io.Reader, meta []byte) (*upload, error) {
+	u := &upload{
+		fileName: fileName,
+		r:        r,
+		meta:     meta,
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+		size:     -1,
+	}
+
+	if s, ok := r.(io.Seeker); ok {
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			u.seekable = true
+			u.start = start
+		}
+	}
+
+	if n, ok := readerSize(r); ok {
+		var cw countingWriter
+		if err := u.write(&cw, strings.NewReader("")); err != nil {
+			return nil, err
+		}
+		u.size = cw.n + n
+	}
+
+	return u, nil
+}
+
+// contentType returns Content-Type header value with the form boundary
+func (u *upload) contentType() string {
+	return "multipart/form-data; boundary=" + u.boundary
+}
+
+// write writes the whole form with a file from r to w
+func (u *upload) write(w io.Writer, r io.Reader) error {
+	mpw := multipart.NewWriter(w)
+	if err := mpw.SetBoundary(u.boundary); err != nil {
+		return err
+	}
+
+	fpart, err := mpw.CreateFormFile("attachment", u.fileName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(fpart, r); err != nil {
+		return fmt.Errorf("can't upload file %s: %w", u.fileName, err)
+	}
+
+	jpart, err := mpw.CreateFormField("_json")
+	if err != nil {
+		return err
+	}
+
+	if _, err := jpart.Write(u.meta); err != nil {
+		return err
+	}
+
+	return mpw.Close()
+}
+
+// body returns a new request body that streams the form
+func (u *upload) body() io.ReadCloser {
+	pr, pw := io.Pipe()
+	b := &uploadBody{
+		up:   u,
+		pr:   pr,
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+
+	u.mu.Lock()
+	u.cur = b
+	u.mu.Unlock()
+
+	return b
+}
+
+// rewind stops the current body, seeks the file to its start and returns
+// a new body. It is used as http.Request.GetBody.
+func (u *upload) rewind() (io.ReadCloser, error) {
+	u.mu.Lock()
+	cur := u.cur
+	u.mu.Unlock()
+
+	if cur != nil {
+		cur.stop()
+	}
+
+	if _, err := u.r.(io.Seeker).Seek(u.start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return u.body(), nil
+}
+
+// uploadBody is a pipe which is written by a goroutine started on the first
+// Read, so no goroutine leaks if the request is never sent
+type uploadBody struct {
+	up   *upload
+	pr   *io.PipeReader
+	pw   *io.PipeWriter
+	once sync.Once
+	done chan struct{}
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			defer close(b.done)
+			b.pw.CloseWithError(b.up.write(b.pw, b.up.r))
+		}()
+	})
+
+	return b.pr.Read(p)
+}
+
+func (b *uploadBody) Close() error {
+	return b.pr.Close()
+}
+
+// stop closes the body and waits for the writing goroutine to finish
+func (b *uploadBody) stop() {
+	b.pr.Close()
+	b.once.Do(func() {
+		close(b.done)
+	})
+	<-b.done
+}
+
+// readerSize returns a number of bytes left in r if it is known
+func readerSize(r io.Reader) (int64, bool) {
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len()), true
+	}
+
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return 0, false
+	}
+
+	cur, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false
+	}
+
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false
+	}
+
+	if _, err := s.Seek(cur, io.SeekStart); err != nil {
+		return 0, false
+	}
+
+	return end - cur, true
+}
+
+// countingWriter counts bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFileRequest(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+	client.Opts.Retry = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	content := strings.Repeat("MZ", 1<<16)
+
+	var attempts int
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.ContentLength <= int64(len(content)) {
+			t.Errorf("ContentLength = %d, want more than file size %d", r.ContentLength, len(content))
+		}
+
+		f, fh, err := r.FormFile("attachment")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		b, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fh.Filename != "sample.exe" || string(b) != content {
+			t.Errorf("attempt %d: got file %s of %d bytes", attempts, fh.Filename, len(b))
+		}
+		var meta FileTaskMeta
+		if err := json.Unmarshal([]byte(r.FormValue("_json")), &meta); err != nil || meta.DataType != "file" {
+			t.Errorf("_json = %s, want file data type", r.FormValue("_json"))
+		}
+
+		// the first attempt is rate limited, so the body must be rewound
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+	})
+
+	j, _, err := client.Analyzers.StartJob(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", &FileTask{
+		FileTaskMeta: FileTaskMeta{DataType: "file"},
+		FileName:     "sample.exe",
+		Reader:       strings.NewReader(content),
+	})
+	if err != nil {
+		t.Fatalf("StartJob returned error: %v", err)
+	}
+	if j.ID != "AWOsZ3pPqNgGAnpM4Ui0" || attempts != 2 {
+		t.Errorf("job %s started in %d attempts", j.ID, attempts)
+	}
+}
+
+func TestNewFileRequestReadError(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+analyzersURL+"/c0b3f12a64d3fa2010ef2df4950d17b4/run", func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.Write([]byte(`{"id":"AWOsZ3pPqNgGAnpM4Ui0"}`))
+	})
+
+	errRead := errors.New("disk is on fire")
+	_, _, err := client.Analyzers.StartJob(context.Background(), "c0b3f12a64d3fa2010ef2df4950d17b4", &FileTask{
+		FileTaskMeta: FileTaskMeta{DataType: "file"},
+		FileName:     "sample.exe",
+		Reader:       io.MultiReader(strings.NewReader("MZ"), &errReader{errRead}),
+	})
+	if !errors.Is(err, errRead) {
+		t.Fatalf("StartJob returned %v, want %v", err, errRead)
+	}
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -1,7 +1,9 @@
 package cortex
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 )
 
@@ -28,6 +30,15 @@ type User struct {
 // UserService is an interface for managing users
 type UserService interface {
 	Current(context.Context) (*User, *http.Response, error)
+	Get(context.Context, string) (*User, *http.Response, error)
+	List(context.Context, string) ([]User, *http.Response, error)
+	Create(context.Context, *User, string) (*User, *http.Response, error)
+	Update(context.Context, string, *User) (*User, *http.Response, error)
+	SetPassword(context.Context, string, string) (*http.Response, error)
+	ChangePassword(context.Context, string, string, string) (*http.Response, error)
+	RenewKey(context.Context, string) (string, *http.Response, error)
+	GetKey(context.Context, string) (string, *http.Response, error)
+	RevokeKey(context.Context, string) (*http.Response, error)
 }
 
 // UserServiceOp handles user specific methods from Cortex API
@@ -50,3 +61,158 @@ func (u *UserServiceOp) Current(ctx context.Context) (*User, *http.Response, err
 
 	return &user, resp, nil
 }
+
+// Get retrieves a user by its login
+func (u *UserServiceOp) Get(ctx context.Context, id string) (*User, *http.Response, error) {
+	req, err := u.client.NewRequest("GET", fmt.Sprintf(usersURL+"/%s", id), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user User
+	resp, err := u.client.Do(ctx, req, &user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &user, resp, nil
+}
+
+// List all users of the organization with pagination
+func (u *UserServiceOp) List(ctx context.Context, org string) ([]User, *http.Response, error) {
+	oso := &OrganizationServiceOp{u.client}
+	return oso.Users(ctx, org)
+}
+
+// Create a new user. ID is used as the user's login, Name, Roles and
+// Organization are also required. The password is optional, the user can
+// authenticate with an API key instead.
+func (u *UserServiceOp) Create(ctx context.Context, user *User, password string) (*User, *http.Response, error) {
+	fields := map[string]interface{}{
+		"login":        user.ID,
+		"name":         user.Name,
+		"roles":        user.Roles,
+		"organization": user.Organization,
+	}
+	if password != "" {
+		fields["password"] = password
+	}
+
+	req, err := u.client.NewRequest("POST", usersURL, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created User
+	resp, err := u.client.Do(ctx, req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update user's name, roles and status, e.g. "Ok" or "Locked". Empty fields
+// are left untouched.
+func (u *UserServiceOp) Update(ctx context.Context, id string, user *User) (*User, *http.Response, error) {
+	fields := make(map[string]interface{})
+	if user.Name != "" {
+		fields["name"] = user.Name
+	}
+	if user.Roles != nil {
+		fields["roles"] = user.Roles
+	}
+	if user.Status != "" {
+		fields["status"] = user.Status
+	}
+
+	req, err := u.client.NewRequest("PATCH", fmt.Sprintf(usersURL+"/%s", id), fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updated User
+	resp, err := u.client.Do(ctx, req, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// SetPassword sets a new password for the user without knowing the current
+// one. Requires admin permissions.
+func (u *UserServiceOp) SetPassword(ctx context.Context, id, password string) (*http.Response, error) {
+	if err := u.client.supports(ctx, "setting password", func(c *Capabilities) bool {
+		return c.SetPassword
+	}); err != nil {
+		return nil, err
+	}
+
+	req, err := u.client.NewRequest("POST", fmt.Sprintf(usersURL+"/%s/password/set", id), map[string]string{
+		"password": password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.Do(ctx, req, nil)
+	return resp, unsupportedError(err)
+}
+
+// ChangePassword changes the user's password from current to a new one
+func (u *UserServiceOp) ChangePassword(ctx context.Context, id, current, password string) (*http.Response, error) {
+	if err := u.client.supports(ctx, "changing password", func(c *Capabilities) bool {
+		return c.ChangePassword
+	}); err != nil {
+		return nil, err
+	}
+
+	req, err := u.client.NewRequest("POST", fmt.Sprintf(usersURL+"/%s/password/change", id), map[string]string{
+		"currentPassword": current,
+		"password":        password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.Do(ctx, req, nil)
+	return resp, unsupportedError(err)
+}
+
+// RenewKey generates a new API key for the user and returns it. The previous
+// key is revoked.
+func (u *UserServiceOp) RenewKey(ctx context.Context, id string) (string, *http.Response, error) {
+	return u.key(ctx, "POST", fmt.Sprintf(usersURL+"/%s/key/renew", id))
+}
+
+// GetKey retrieves the user's API key
+func (u *UserServiceOp) GetKey(ctx context.Context, id string) (string, *http.Response, error) {
+	return u.key(ctx, "GET", fmt.Sprintf(usersURL+"/%s/key", id))
+}
+
+// RevokeKey removes the user's API key
+func (u *UserServiceOp) RevokeKey(ctx context.Context, id string) (*http.Response, error) {
+	req, err := u.client.NewRequest("DELETE", fmt.Sprintf(usersURL+"/%s/key", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.client.Do(ctx, req, nil)
+}
+
+// key requests an API key, which Cortex returns as a plain text
+func (u *UserServiceOp) key(ctx context.Context, method, urlStr string) (string, *http.Response, error) {
+	req, err := u.client.NewRequest(method, urlStr, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var key bytes.Buffer
+	resp, err := u.client.Do(ctx, req, &key)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return key.String(), resp, nil
+}
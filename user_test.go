@@ -0,0 +1,91 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreateUser(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+usersURL, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"login":        "analyst",
+			"name":         "Analyst",
+			"roles":        []interface{}{"read", "analyze"},
+			"organization": "acme",
+			"password":     "secret",
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("create body = %+v, want %+v", body, want)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write(userJSON)
+	})
+
+	got, _, err := client.Users.Create(context.Background(), &User{
+		ID:           "analyst",
+		Name:         "Analyst",
+		Roles:        []string{"read", "analyze"},
+		Organization: "acme",
+	}, "secret")
+	if err != nil {
+		t.Errorf("Users.Create returned error: %v", err)
+	}
+	if want := wantUser; !reflect.DeepEqual(got, want) {
+		t.Errorf("Users.Create = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenewKey(t *testing.T) {
+	client, mux, _, closer := setup()
+	defer closer()
+
+	mux.HandleFunc("/"+usersURL+"/analyst/key/renew", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		w.Write([]byte("Vz3Jm5ZyRQB0fNa2iA1d0PzOHkG9yKs1"))
+	})
+
+	got, _, err := client.Users.RenewKey(context.Background(), "analyst")
+	if err != nil {
+		t.Errorf("Users.RenewKey returned error: %v", err)
+	}
+	if want := "Vz3Jm5ZyRQB0fNa2iA1d0PzOHkG9yKs1"; got != want {
+		t.Errorf("Users.RenewKey = %s, want %s", got, want)
+	}
+}
+
+var userJSON = []byte(`
+{
+  "id": "analyst",
+  "name": "Analyst",
+  "roles": ["read", "analyze"],
+  "organization": "acme",
+  "status": "Ok",
+  "createdAt": 1527178197172,
+  "createdBy": "admin",
+  "hasKey": false,
+  "hasPassword": true
+}`)
+
+var wantUser = &User{
+	ID:           "analyst",
+	Name:         "Analyst",
+	Roles:        []string{"read", "analyze"},
+	Organization: "acme",
+	Status:       "Ok",
+	CreatedAt:    1527178197172,
+	CreatedBy:    "admin",
+	HasPassword:  true,
+}
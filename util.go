@@ -9,26 +9,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/asaskevich/govalidator"
 )
 
-const (
-	// TxSafe is a safe taxonomy level
-	TxSafe = "safe"
-
-	// TxInfo is an info taxonomy level
-	TxInfo = "info"
-
-	// TxSuspicious is a suspicious taxonomy level
-	TxSuspicious = "suspicious"
-
-	// TxMalicious is a malicious taxonomy level
-	TxMalicious = "malicious"
-)
-
 var (
 	domain = `([a-zA-Z0-9_]{1}[a-zA-Z0-9_-]{0,62}){1}(\.[a-zA-Z]{1}[a-zA-Z]{0,62})+[\._]?`
 	// inspired by govalidator.CreditCard
@@ -48,6 +35,10 @@ var (
 	// default
 	DefaultInput = os.Stdin
 
+	// DefaultOutput represents an analyzer or responder output that is used
+	// by default
+	DefaultOutput io.Writer = os.Stdout
+
 	errTooHighTLP = errors.New("TLP is higher than allowed")
 	errTooHighPAP = errors.New("PAP is higher than allowed")
 )
@@ -80,12 +71,30 @@ type JobInput struct {
 	Config      cfg               `json:"config,omitempty"`
 	Message     string            `json:"message,omitempty"`
 	Parameters  map[string]string `json:"parameters,omitempty"`
+
+	// dir is a Cortex 3 job directory, it's empty if the job is passed
+	// through stdin and stdout
+	dir string
+
+	// artifacts are added by the analyzer explicitly
+	artifacts []ExtractedArtifact
 }
 
-// ExtractedArtifact is used for artifacts with slightly different structure
+// ExtractedArtifact is an artifact found by an analyzer. File artifacts have
+// a File written to the job output directory instead of a Value.
+//
+// Type and Value keep their Go names for compatibility, but are encoded as
+// dataType and data, the keys Cortex reads artifacts from. Analyzers
+// written before this version produced type and value keys instead.
 type ExtractedArtifact struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
+	Type        string   `json:"dataType"`
+	Value       string   `json:"data,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	TLP         *TLP     `json:"tlp,omitempty"`
+	File        string   `json:"file,omitempty"`
+	FileName    string   `json:"filename,omitempty"`
+	ContentType string   `json:"contentType,omitempty"`
 }
 
 //AnalyzerReport is the report that analyzer app should return in case everything is okay
@@ -105,79 +114,227 @@ type AnalyzerError struct {
 
 // PrintError returns unsuccessful Report with an error message
 func (j *JobInput) PrintError(err error) {
-	r := &AnalyzerError{
+	if werr := j.print(func(w io.Writer) error {
+		return j.WriteError(w, err)
+	}); werr != nil {
+		log.Fatal(werr)
+	}
+	os.Exit(1)
+}
+
+// PrintReport constructs Report by raw body and taxonomies
+func (j *JobInput) PrintReport(body interface{}, taxes []Taxonomy) {
+	if err := j.print(func(w io.Writer) error {
+		return j.WriteReport(w, body, taxes)
+	}); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(0)
+}
+
+// print writes the output to the job directory if any, otherwise to
+// DefaultOutput
+func (j *JobInput) print(write func(io.Writer) error) error {
+	if j.dir == "" {
+		return write(DefaultOutput)
+	}
+
+	f, err := createJobOutput(j.dir)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// WriteError writes unsuccessful Report with an error message to w
+func (j *JobInput) WriteError(w io.Writer, err error) error {
+	return writeJSON(w, &AnalyzerError{
 		Success:      false,
 		ErrorMessage: err.Error(),
 		Input:        j,
+	})
+}
+
+// WriteReport writes Report constructed by raw body and taxonomies to w
+func (j *JobInput) WriteReport(w io.Writer, body interface{}, taxes []Taxonomy) error {
+	r, err := j.NewReport(body, taxes)
+	if err != nil {
+		return err
 	}
 
-	body, merr := json.Marshal(r)
-	if merr != nil {
-		log.Fatal(merr)
+	return writeJSON(w, r)
+}
+
+// NewReport constructs successful Report by raw body and taxonomies,
+// artifacts are extracted from the body if it's enabled in the config
+func (j *JobInput) NewReport(body interface{}, taxes []Taxonomy) (*AnalyzerReport, error) {
+	r := &AnalyzerReport{
+		FullReport: body,
+		Summary:    &Summary{taxes},
+	}
+	if err := j.completeReport(r); err != nil {
+		return nil, err
 	}
 
-	fmt.Print(string(body))
-	os.Exit(1)
+	return r, nil
 }
 
-// PrintReport constructs Report by raw body and taxonomies
-func (j *JobInput) PrintReport(body interface{}, taxes []Taxonomy) {
-	artifacts := make([]ExtractedArtifact, 0)
-	if j.Config.NeedExtractArtifacts() && body != nil {
-		mb, err := json.Marshal(body)
+// completeReport marks the report successful, fills its missing parts and
+// merges its artifacts with added and auto-extracted ones
+func (j *JobInput) completeReport(r *AnalyzerReport) error {
+	r.Success = true
+
+	var extracted []ExtractedArtifact
+	if j.Config.NeedExtractArtifacts() && r.FullReport != nil {
+		mb, err := json.Marshal(r.FullReport)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
-		artifacts = ExtractArtifacts(string(mb))
+		extracted = ExtractArtifacts(string(mb))
 	}
+	r.Artifacts = mergeArtifacts(append(r.Artifacts, j.artifacts...), extracted)
 
-	if body == nil {
-		body = struct{}{}
+	if r.FullReport == nil {
+		r.FullReport = struct{}{}
+	}
+	if r.Summary == nil {
+		r.Summary = &Summary{}
 	}
 
-	r := &AnalyzerReport{
-		Success:    true,
-		Artifacts:  artifacts,
-		FullReport: body,
-		Summary:    &Summary{taxes},
+	return nil
+}
+
+// AddArtifact adds an artifact to the report, e.g. with a message, tags or
+// TLP. It takes precedence over an auto-extracted artifact with the same
+// value, whatever type the extractor gives it. It's not safe for concurrent
+// use.
+func (j *JobInput) AddArtifact(a ExtractedArtifact) {
+	j.artifacts = append(j.artifacts, a)
+}
+
+// AddFileArtifact writes the file content from r to the job output directory
+// and adds it to the report as a file artifact named fileName. Type of the
+// artifact defaults to "file". File artifacts are supported only within
+// a Cortex 3 job directory.
+func (j *JobInput) AddFileArtifact(fileName string, r io.Reader, a ExtractedArtifact) error {
+	if j.dir == "" {
+		return errors.New("file artifacts require a Cortex 3 job directory")
 	}
-	b, err := json.Marshal(r)
+
+	dir := filepath.Join(j.dir, "output")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "artifact-")
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("can't write file artifact %s: %w", fileName, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
 	}
 
-	fmt.Print(string(b))
-	os.Exit(0)
+	if a.Type == "" {
+		a.Type = "file"
+	}
+	a.File = filepath.Base(f.Name())
+	a.FileName = fileName
+	j.AddArtifact(a)
+
+	return nil
 }
 
-func (j *JobInput) allowedTLP() bool {
+// mergeArtifacts removes duplicates of explicit artifacts keeping the first
+// one and appends extracted artifacts that are not set explicitly. Explicit
+// data artifacts are compared by type and value, file artifacts by file.
+// Extracted artifacts are compared by value only, since the extractor types,
+// e.g. ipv4, differ from the data types analyzers use, e.g. ip.
+func mergeArtifacts(explicit, extracted []ExtractedArtifact) []ExtractedArtifact {
+	merged := make([]ExtractedArtifact, 0, len(explicit)+len(extracted))
+	seen := make(map[string]bool)
+	values := make(map[string]bool)
+	for _, a := range explicit {
+		key := "data:" + a.Type + ":" + a.Value
+		if a.File != "" {
+			key = "file:" + a.File
+		}
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		if a.File == "" {
+			values[a.Value] = true
+		}
+		merged = append(merged, a)
+	}
+
+	for _, a := range extracted {
+		if values[a.Value] {
+			continue
+		}
+
+		values[a.Value] = true
+		merged = append(merged, a)
+	}
+
+	return merged
+}
+
+// HTTPClient returns *http.Client that uses a proxy from the config if any
+func (j *JobInput) HTTPClient() *http.Client {
+	return j.Config.httpClient()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func (c cfg) allowedTLP(tlp TLP) bool {
 	// if maxtlp is not set, make it to maximum
 	var maxtlp TLP
-	maxtlpFloat, err := j.Config.GetFloat("max_tlp")
+	maxtlpFloat, err := c.GetFloat("max_tlp")
 	if err != nil {
 		maxtlp = TLPRed
 	} else {
 		maxtlp = TLP(maxtlpFloat)
 	}
 
-	if j.TLP > maxtlp {
+	if tlp > maxtlp {
 		return false
 	}
 	return true
 }
 
-func (j *JobInput) allowedPAP() bool {
+func (c cfg) allowedPAP(pap PAP) bool {
 	// if maxpap is not set, make it to maximum
 	var maxpap PAP
-	maxpapFloat, err := j.Config.GetFloat("max_pap")
+	maxpapFloat, err := c.GetFloat("max_pap")
 	if err != nil {
 		maxpap = PAPRed
 	} else {
 		maxpap = PAP(maxpapFloat)
 	}
 
-	if j.PAP > maxpap {
+	if pap > maxpap {
 		return false
 	}
 	return true
@@ -325,53 +482,76 @@ func (c cfg) GetBool(key string) (bool, error) {
 }
 
 // NewInput grabs DefaultInput (stdin by default) and bootstraps *JobInput and
-// *http.Client
+// *http.Client. If a Cortex 3 job directory is detected by DetectJobDir, the
+// input is read from it and reports are written to it.
 func NewInput() (*JobInput, *http.Client, error) {
-	return newInput(DefaultInput)
+	if dir := DetectJobDir(); dir != "" {
+		return newDirInput(dir)
+	}
+
+	return newInput(DefaultInput, "")
 }
 
-func newInput(r io.Reader) (*JobInput, *http.Client, error) {
+func newInput(r io.Reader, dir string) (*JobInput, *http.Client, error) {
 	in, err := parseInput(r)
 	if err != nil {
 		return nil, http.DefaultClient, err
 	}
+	in.setDir(dir)
+
+	if err := in.Config.checkSharing(in.TLP, in.PAP); err != nil {
+		in.PrintError(err)
+	}
+
+	return in, in.Config.httpClient(), nil
+}
+
+func (j *JobInput) checkTLP() error {
+	return j.Config.checkTLP(j.TLP)
+}
+
+func (j *JobInput) checkPAP() error {
+	return j.Config.checkPAP(j.PAP)
+}
 
+// checkSharing checks both TLP and PAP and joins the errors if any
+func (c cfg) checkSharing(tlp TLP, pap PAP) error {
 	var errs []string
-	if terr := in.checkTLP(); terr != nil {
+	if terr := c.checkTLP(tlp); terr != nil {
 		errs = append(errs, terr.Error())
 	}
 
-	if perr := in.checkPAP(); perr != nil {
+	if perr := c.checkPAP(pap); perr != nil {
 		errs = append(errs, perr.Error())
 	}
 
 	if len(errs) > 0 {
-		in.PrintError(errors.New(strings.Join(errs, ", ")))
+		return errors.New(strings.Join(errs, ", "))
 	}
 
-	return in, in.Config.httpClient(), nil
+	return nil
 }
 
-func (j *JobInput) checkTLP() error {
-	v, err := j.Config.GetBool("check_tlp")
+func (c cfg) checkTLP(tlp TLP) error {
+	v, err := c.GetBool("check_tlp")
 	if err != nil {
 		return nil // if check_tlp is not found do not check for it
 	}
 
-	if v && !j.allowedTLP() {
+	if v && !c.allowedTLP(tlp) {
 		return errTooHighTLP
 	}
 
 	return nil
 }
 
-func (j *JobInput) checkPAP() error {
-	v, err := j.Config.GetBool("check_pap")
+func (c cfg) checkPAP(pap PAP) error {
+	v, err := c.GetBool("check_pap")
 	if err != nil {
-		return nil // if check_tlp is not found do not check for it
+		return nil // if check_pap is not found do not check for it
 	}
 
-	if v && !j.allowedPAP() {
+	if v && !c.allowedPAP(pap) {
 		return errTooHighPAP
 	}
 